@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/lint"
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+	"github.com/greenbone/gvm-openapi-emulator/logger"
+)
+
+// runLint implements `emulator lint`. It returns 0 for a clean tree,
+// 1 when findings were reported and 2 when the check could not run.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	cfg := config.Envs
+
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	specPath := fs.String("spec", cfg.SpecPath, "path to the OpenAPI / Swagger spec (SPEC_PATH)")
+	samplesDir := fs.String("samples", cfg.SamplesDir, "samples directory to check (SAMPLES_DIR)")
+	layout := fs.String("layout", string(cfg.Layout), "sample layout: auto | folders | flat (LAYOUT_MODE)")
+	templates := fs.Bool("templates", cfg.Templates, "render samples as templates before checking them (TEMPLATES_ENABLED)")
+	asJSON := fs.Bool("json", false, "print findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, err := openapi.NewSpecProvider(*specPath, logger.GetLogger())
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "lint: %v\n", err)
+		return 2
+	}
+
+	linter := lint.NewLinter(lint.Config{
+		SamplesDir:       *samplesDir,
+		Layout:           config.LayoutMode(*layout),
+		ScenarioEnabled:  cfg.Scenario.Enabled,
+		ScenarioFilename: cfg.Scenario.Filename,
+		Templates:        *templates,
+	}, spec, openapi.NewRouterProvider(spec.GetSpec()))
+
+	findings, err := linter.Run()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "lint: %v\n", err)
+		return 2
+	}
+
+	if *asJSON {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(findings)
+	} else {
+		tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		for _, f := range findings {
+			where := f.File
+			if where == "" {
+				where = f.Route
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", f.Kind, where, f.Message)
+		}
+		_ = tw.Flush()
+		_, _ = fmt.Fprintf(stdout, "%d problem(s) in %s\n", len(findings), *samplesDir)
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func isLintCommand() bool {
+	return len(os.Args) > 1 && os.Args[1] == "lint"
+}
@@ -5,12 +5,18 @@
 package main
 
 import (
+	"os"
+
 	"github.com/greenbone/gvm-openapi-emulator/config"
 	"github.com/greenbone/gvm-openapi-emulator/internal/server"
 	"github.com/greenbone/gvm-openapi-emulator/logger"
 )
 
 func main() {
+	if isLintCommand() {
+		os.Exit(runLint(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	cfg := config.Envs
 	log := logger.GetLogger()
 
@@ -21,6 +27,18 @@ func main() {
 		FallbackMode:   cfg.FallbackMode,
 		ValidationMode: cfg.ValidationMode,
 		Layout:         cfg.Layout,
+
+		ResponseValidationMode: cfg.ResponseValidationMode,
+		Resources:              cfg.Resources,
+		Templates:              cfg.Templates,
+
+		Journal:  cfg.Journal,
+		Upstream: cfg.Upstream,
+		Record:   cfg.Record,
+
+		Watch:     cfg.Watch,
+		BasePath:  cfg.BasePath,
+		Generator: cfg.Generator,
 	})
 	if err != nil {
 		log.Fatalf("failed to init server: %v", err)
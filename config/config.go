@@ -5,6 +5,9 @@
 package config
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/greenbone/gvm-openapi-emulator/utils"
 	"github.com/joho/godotenv"
 )
@@ -22,13 +25,45 @@ type FallbackMode string
 const (
 	FallbackNone           FallbackMode = "none"
 	FallbackOpenAPIExample FallbackMode = "openapi_examples"
+	FallbackProxy          FallbackMode = "proxy" // forward to UPSTREAM_URL
 )
 
+// Steps splits a chained mode such as "openapi_examples,proxy" into the steps
+// that are tried in order.
+func (m FallbackMode) Steps() []FallbackMode {
+	var out []FallbackMode
+	for _, p := range strings.Split(string(m), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, FallbackMode(p))
+		}
+	}
+	return out
+}
+
+func (m FallbackMode) Has(step FallbackMode) bool {
+	for _, s := range m.Steps() {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
 type ValidationMode string
 
 const (
 	ValidationNone     ValidationMode = "none"
 	ValidationRequired ValidationMode = "required"
+	ValidationStrict   ValidationMode = "strict"
+)
+
+type ResponseValidationMode string
+
+const (
+	ResponseValidationNone   ResponseValidationMode = "none"
+	ResponseValidationWarn   ResponseValidationMode = "warn"   // log drift only
+	ResponseValidationHeader ResponseValidationMode = "header" // log drift and add a response header
+	ResponseValidationFail   ResponseValidationMode = "fail"   // replace the sample with a 500
 )
 
 type LayoutMode string
@@ -39,9 +74,78 @@ const (
 	LayoutFlat    LayoutMode = "flat"    // only flat
 )
 
+type ScenarioValidationMode string
+
+const (
+	ScenarioValidationFail ScenarioValidationMode = "fail" // refuse to start on broken scenarios
+	ScenarioValidationWarn ScenarioValidationMode = "warn" // log problems and start anyway
+)
+
+type ClockMode string
+
+const (
+	ClockReal    ClockMode = "real"    // time-mode scenarios follow the wall clock
+	ClockVirtual ClockMode = "virtual" // time only moves through the admin API
+)
+
 type ScenarioConfig struct {
-	Enabled  bool
-	Filename string
+	Enabled    bool
+	Filename   string
+	Validation ScenarioValidationMode
+	Clock      ClockMode
+}
+
+type ResourceConfig struct {
+	Enabled bool
+	IDField string
+}
+
+type JournalConfig struct {
+	Size int
+	File string
+}
+
+type UpstreamConfig struct {
+	URL        string
+	TimeoutSec int
+	// Headers are set on forwarded requests; an empty value removes the header.
+	Headers map[string]string
+}
+
+type BasePathMode string
+
+const (
+	BasePathStrip   BasePathMode = "strip"
+	BasePathRequire BasePathMode = "require"
+)
+
+type BasePathConfig struct {
+	// Paths replace the base paths from servers[].url or basePath when set.
+	Paths []string
+	Mode  BasePathMode
+}
+
+type WatchConfig struct {
+	Enabled    bool
+	IntervalMs int
+}
+
+type GeneratorMode string
+
+const (
+	GeneratorStatic GeneratorMode = "static" // one fixed value per schema
+	GeneratorSeeded GeneratorMode = "seeded" // values derived from the seed, route and path params
+)
+
+// MaxArrayLength caps GENERATOR_ARRAY_LENGTH, so one response cannot grow without bound.
+const MaxArrayLength = 100
+
+type GeneratorConfig struct {
+	Mode GeneratorMode
+	Seed int
+	// ArrayMin and ArrayMax bound the length of generated arrays, 1 to MaxArrayLength.
+	ArrayMin int
+	ArrayMax int
 }
 
 type Config struct {
@@ -55,7 +159,20 @@ type Config struct {
 	ValidationMode ValidationMode
 	Layout         LayoutMode
 
-	Scenario ScenarioConfig
+	ResponseValidationMode ResponseValidationMode
+	Templates              bool
+
+	Scenario  ScenarioConfig
+	Resources ResourceConfig
+	Journal   JournalConfig
+	Upstream  UpstreamConfig
+
+	// Record forwards every request to the upstream and writes the responses as samples.
+	Record bool
+
+	Watch     WatchConfig
+	BasePath  BasePathConfig
+	Generator GeneratorConfig
 }
 
 var Envs = initConfig()
@@ -74,9 +191,103 @@ func initConfig() Config {
 		DebugRoutes:    utils.GetEnvAsBool("DEBUG_ROUTES", false),
 		Layout:         LayoutMode(utils.GetEnv("LAYOUT_MODE", "auto")),
 
+		ResponseValidationMode: ResponseValidationMode(utils.GetEnv("RESPONSE_VALIDATION_MODE", "none")),
+		Templates:              utils.GetEnvAsBool("TEMPLATES_ENABLED", false),
+
 		Scenario: ScenarioConfig{
 			Enabled:  utils.GetEnvAsBool("SCENARIO_ENABLED", true),
 			Filename: utils.GetEnv("SCENARIO_FILENAME", "scenario.json"),
+
+			Validation: ScenarioValidationMode(utils.GetEnv("SCENARIO_VALIDATION", "fail")),
+			Clock:      ClockMode(utils.GetEnv("SCENARIO_CLOCK", "real")),
 		},
+
+		Resources: ResourceConfig{
+			Enabled: utils.GetEnvAsBool("RESOURCES_ENABLED", false),
+			IDField: utils.GetEnv("RESOURCE_ID_FIELD", "id"),
+		},
+
+		Journal: JournalConfig{
+			Size: utils.GetEnvAsInt("JOURNAL_SIZE", 0),
+			File: utils.GetEnv("JOURNAL_FILE", ""),
+		},
+
+		Upstream: UpstreamConfig{
+			URL:        utils.GetEnv("UPSTREAM_URL", ""),
+			TimeoutSec: utils.GetEnvAsInt("UPSTREAM_TIMEOUT", 30),
+			Headers:    parseHeaderRules(utils.GetEnv("UPSTREAM_HEADERS", "")),
+		},
+		Record: utils.GetEnvAsBool("RECORD_MODE", false),
+
+		Watch: WatchConfig{
+			Enabled:    utils.GetEnvAsBool("WATCH_ENABLED", false),
+			IntervalMs: utils.GetEnvAsInt("WATCH_INTERVAL_MS", 1000),
+		},
+
+		BasePath: BasePathConfig{
+			Paths: splitList(utils.GetEnv("BASE_PATH", "")),
+			Mode:  BasePathMode(utils.GetEnv("BASE_PATH_MODE", "strip")),
+		},
+
+		Generator: newGeneratorConfig(),
+	}
+}
+
+func newGeneratorConfig() GeneratorConfig {
+	lo, hi := parseRange(utils.GetEnv("GENERATOR_ARRAY_LENGTH", "1"), 1)
+	// empty arrays are left to a schema's maxItems
+	if lo < 1 {
+		lo, hi = 1, 1
+	}
+	hi = min(hi, MaxArrayLength)
+	lo = min(lo, hi)
+	return GeneratorConfig{
+		Mode:     GeneratorMode(utils.GetEnv("GENERATOR_MODE", "static")),
+		Seed:     utils.GetEnvAsInt("GENERATOR_SEED", 0),
+		ArrayMin: lo,
+		ArrayMax: hi,
+	}
+}
+
+// parseRange reads "n" or "min-max". Invalid or negative input yields def for both.
+func parseRange(raw string, def int) (int, int) {
+	from, to, isRange := strings.Cut(raw, "-")
+	lo, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil || lo < 0 {
+		return def, def
+	}
+	if !isRange {
+		return lo, lo
+	}
+	hi, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil || hi < lo {
+		return def, def
+	}
+	return lo, hi
+}
+
+// parseHeaderRules reads "Name=value,Other=" into a header map.
+// Entries without "=" are ignored.
+func parseHeaderRules(raw string) map[string]string {
+	out := map[string]string{}
+	for _, part := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		out[name] = strings.TrimSpace(value)
+	}
+	return out
+}
+
+// splitList reads a comma separated list, dropping empty entries.
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
 	}
+	return out
 }
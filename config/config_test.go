@@ -21,6 +21,18 @@ func TestInitConfig_Defaults_AllFields(t *testing.T) {
 	_ = os.Unsetenv("LAYOUT_MODE")
 	_ = os.Unsetenv("SCENARIO_ENABLED")
 	_ = os.Unsetenv("SCENARIO_FILENAME")
+	_ = os.Unsetenv("RESPONSE_VALIDATION_MODE")
+	_ = os.Unsetenv("SCENARIO_VALIDATION")
+	_ = os.Unsetenv("SCENARIO_CLOCK")
+	_ = os.Unsetenv("RESOURCES_ENABLED")
+	_ = os.Unsetenv("TEMPLATES_ENABLED")
+	_ = os.Unsetenv("RESOURCE_ID_FIELD")
+	_ = os.Unsetenv("UPSTREAM_URL")
+	_ = os.Unsetenv("UPSTREAM_TIMEOUT")
+	_ = os.Unsetenv("RECORD_MODE")
+	_ = os.Unsetenv("UPSTREAM_HEADERS")
+	_ = os.Unsetenv("JOURNAL_SIZE")
+	_ = os.Unsetenv("JOURNAL_FILE")
 
 	cfg := initConfig()
 
@@ -51,6 +63,9 @@ func TestInitConfig_Defaults_AllFields(t *testing.T) {
 	if cfg.Layout != LayoutAuto {
 		t.Fatalf("Layout: expected %q, got %q", LayoutAuto, cfg.Layout)
 	}
+	if cfg.ResponseValidationMode != ResponseValidationNone {
+		t.Fatalf("ResponseValidationMode: expected %q, got %q", ResponseValidationNone, cfg.ResponseValidationMode)
+	}
 
 	if cfg.Scenario.Enabled != true {
 		t.Fatalf("Scenario.Enabled: expected %v, got %v", true, cfg.Scenario.Enabled)
@@ -58,6 +73,39 @@ func TestInitConfig_Defaults_AllFields(t *testing.T) {
 	if cfg.Scenario.Filename != "scenario.json" {
 		t.Fatalf("Scenario.Filename: expected %q, got %q", "scenario.json", cfg.Scenario.Filename)
 	}
+	if cfg.Scenario.Validation != ScenarioValidationFail {
+		t.Fatalf("Scenario.Validation: expected %q, got %q", ScenarioValidationFail, cfg.Scenario.Validation)
+	}
+	if cfg.Scenario.Clock != ClockReal {
+		t.Fatalf("Scenario.Clock: expected %q, got %q", ClockReal, cfg.Scenario.Clock)
+	}
+	if cfg.Templates != false {
+		t.Fatalf("Templates: expected %v, got %v", false, cfg.Templates)
+	}
+	if cfg.Resources.Enabled != false {
+		t.Fatalf("Resources.Enabled: expected %v, got %v", false, cfg.Resources.Enabled)
+	}
+	if cfg.Resources.IDField != "id" {
+		t.Fatalf("Resources.IDField: expected %q, got %q", "id", cfg.Resources.IDField)
+	}
+	if cfg.Upstream.URL != "" {
+		t.Fatalf("Upstream.URL: expected empty, got %q", cfg.Upstream.URL)
+	}
+	if cfg.Upstream.TimeoutSec != 30 {
+		t.Fatalf("Upstream.TimeoutSec: expected %d, got %d", 30, cfg.Upstream.TimeoutSec)
+	}
+	if cfg.Record != false {
+		t.Fatalf("Record: expected %v, got %v", false, cfg.Record)
+	}
+	if len(cfg.Upstream.Headers) != 0 {
+		t.Fatalf("Upstream.Headers: expected none, got %v", cfg.Upstream.Headers)
+	}
+	if cfg.Journal.Size != 0 {
+		t.Fatalf("Journal.Size: expected %d, got %d", 0, cfg.Journal.Size)
+	}
+	if cfg.Journal.File != "" {
+		t.Fatalf("Journal.File: expected empty, got %q", cfg.Journal.File)
+	}
 }
 
 func TestInitConfig_Overrides_AllFields(t *testing.T) {
@@ -70,9 +118,21 @@ func TestInitConfig_Overrides_AllFields(t *testing.T) {
 	t.Setenv("FALLBACK_MODE", "none")
 	t.Setenv("DEBUG_ROUTES", "1")
 	t.Setenv("LAYOUT_MODE", "folders")
+	t.Setenv("RESPONSE_VALIDATION_MODE", "fail")
 
 	t.Setenv("SCENARIO_ENABLED", "false")
 	t.Setenv("SCENARIO_FILENAME", "my-scenario.json")
+	t.Setenv("SCENARIO_VALIDATION", "warn")
+	t.Setenv("SCENARIO_CLOCK", "virtual")
+	t.Setenv("RESOURCES_ENABLED", "true")
+	t.Setenv("TEMPLATES_ENABLED", "true")
+	t.Setenv("RESOURCE_ID_FIELD", "uuid")
+	t.Setenv("UPSTREAM_URL", "http://openvasd:3000")
+	t.Setenv("UPSTREAM_TIMEOUT", "5")
+	t.Setenv("RECORD_MODE", "true")
+	t.Setenv("UPSTREAM_HEADERS", "X-API-KEY=secret, Cookie=,broken")
+	t.Setenv("JOURNAL_SIZE", "10")
+	t.Setenv("JOURNAL_FILE", "/tmp/journal.jsonl")
 
 	cfg := initConfig()
 
@@ -103,6 +163,9 @@ func TestInitConfig_Overrides_AllFields(t *testing.T) {
 	if cfg.Layout != LayoutFolders {
 		t.Fatalf("Layout: expected %q, got %q", LayoutFolders, cfg.Layout)
 	}
+	if cfg.ResponseValidationMode != ResponseValidationFail {
+		t.Fatalf("ResponseValidationMode: expected %q, got %q", ResponseValidationFail, cfg.ResponseValidationMode)
+	}
 
 	if cfg.Scenario.Enabled != false {
 		t.Fatalf("Scenario.Enabled: expected %v, got %v", false, cfg.Scenario.Enabled)
@@ -110,6 +173,39 @@ func TestInitConfig_Overrides_AllFields(t *testing.T) {
 	if cfg.Scenario.Filename != "my-scenario.json" {
 		t.Fatalf("Scenario.Filename: expected %q, got %q", "my-scenario.json", cfg.Scenario.Filename)
 	}
+	if cfg.Scenario.Validation != ScenarioValidationWarn {
+		t.Fatalf("Scenario.Validation: expected %q, got %q", ScenarioValidationWarn, cfg.Scenario.Validation)
+	}
+	if cfg.Scenario.Clock != ClockVirtual {
+		t.Fatalf("Scenario.Clock: expected %q, got %q", ClockVirtual, cfg.Scenario.Clock)
+	}
+	if cfg.Templates != true {
+		t.Fatalf("Templates: expected %v, got %v", true, cfg.Templates)
+	}
+	if cfg.Resources.Enabled != true {
+		t.Fatalf("Resources.Enabled: expected %v, got %v", true, cfg.Resources.Enabled)
+	}
+	if cfg.Resources.IDField != "uuid" {
+		t.Fatalf("Resources.IDField: expected %q, got %q", "uuid", cfg.Resources.IDField)
+	}
+	if cfg.Upstream.URL != "http://openvasd:3000" {
+		t.Fatalf("Upstream.URL: expected %q, got %q", "http://openvasd:3000", cfg.Upstream.URL)
+	}
+	if cfg.Upstream.TimeoutSec != 5 {
+		t.Fatalf("Upstream.TimeoutSec: expected %d, got %d", 5, cfg.Upstream.TimeoutSec)
+	}
+	if cfg.Record != true {
+		t.Fatalf("Record: expected %v, got %v", true, cfg.Record)
+	}
+	if len(cfg.Upstream.Headers) != 2 || cfg.Upstream.Headers["X-API-KEY"] != "secret" || cfg.Upstream.Headers["Cookie"] != "" {
+		t.Fatalf("Upstream.Headers: unexpected %v", cfg.Upstream.Headers)
+	}
+	if cfg.Journal.Size != 10 {
+		t.Fatalf("Journal.Size: expected %d, got %d", 10, cfg.Journal.Size)
+	}
+	if cfg.Journal.File != "/tmp/journal.jsonl" {
+		t.Fatalf("Journal.File: expected %q, got %q", "/tmp/journal.jsonl", cfg.Journal.File)
+	}
 }
 
 func TestInitConfig_BoolParsing_DebugRoutesVariants(t *testing.T) {
@@ -163,3 +259,55 @@ func TestInitConfig_BoolParsing_ScenarioEnabledVariants(t *testing.T) {
 		})
 	}
 }
+
+func TestFallbackMode_Steps(t *testing.T) {
+	m := FallbackMode(" openapi_examples , proxy,")
+	steps := m.Steps()
+	if len(steps) != 2 || steps[0] != FallbackOpenAPIExample || steps[1] != FallbackProxy {
+		t.Fatalf("unexpected steps %v", steps)
+	}
+	if !m.Has(FallbackProxy) || FallbackNone.Has(FallbackProxy) {
+		t.Fatalf("Has: unexpected result for %q", m)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		raw    string
+		lo, hi int
+	}{
+		{"3", 3, 3},
+		{"2-10", 2, 10},
+		{" 0 - 4 ", 0, 4},
+		{"", 1, 1},
+		{"x", 1, 1},
+		{"5-2", 1, 1},
+		{"-3", 1, 1},
+	}
+	for _, tc := range cases {
+		lo, hi := parseRange(tc.raw, 1)
+		if lo != tc.lo || hi != tc.hi {
+			t.Fatalf("parseRange(%q): expected %d-%d, got %d-%d", tc.raw, tc.lo, tc.hi, lo, hi)
+		}
+	}
+}
+
+func TestNewGeneratorConfig_ArrayLengthBounds(t *testing.T) {
+	cases := []struct {
+		raw    string
+		lo, hi int
+	}{
+		{"5-50", 5, 50},
+		{"0", 1, 1},
+		{"0-5", 1, 1},
+		{"500", 100, 100},
+		{"50-500", 50, 100},
+	}
+	for _, tc := range cases {
+		t.Setenv("GENERATOR_ARRAY_LENGTH", tc.raw)
+		g := newGeneratorConfig()
+		if g.ArrayMin != tc.lo || g.ArrayMax != tc.hi {
+			t.Fatalf("GENERATOR_ARRAY_LENGTH=%q: expected %d-%d, got %d-%d", tc.raw, tc.lo, tc.hi, g.ArrayMin, g.ArrayMax)
+		}
+	}
+}
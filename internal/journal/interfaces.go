@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package journal
+
+type IJournal interface {
+	Add(e Entry) Entry
+	List(f Filter) []Entry
+	Clear() int
+	Close() error
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package journal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Journal keeps the most recent entries in memory and optionally appends
+// every entry to a JSONL file.
+type Journal struct {
+	mu      sync.Mutex
+	size    int
+	nextID  int64
+	entries []Entry
+	file    *os.File
+	log     *logrus.Logger
+}
+
+func NewJournal(cfg Config, log *logrus.Logger) (IJournal, error) {
+	j := &Journal{size: cfg.Size, log: log}
+	if strings.TrimSpace(cfg.File) != "" {
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) // #nosec G304 -- configured journal file
+		if err != nil {
+			return nil, fmt.Errorf("open journal file: %w", err)
+		}
+		j.file = f
+	}
+	return j, nil
+}
+
+// Add assigns the next id to e, stores it and returns it.
+func (j *Journal) Add(e Entry) Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.nextID++
+	e.ID = j.nextID
+
+	if j.size > 0 {
+		if len(j.entries) >= j.size {
+			j.entries = append(j.entries[len(j.entries)-j.size+1:], e)
+		} else {
+			j.entries = append(j.entries, e)
+		}
+	}
+
+	if j.file != nil {
+		b, err := json.Marshal(e)
+		if err == nil {
+			_, err = j.file.Write(append(b, '\n'))
+		}
+		if err != nil {
+			j.log.WithError(err).WithField("id", e.ID).Warn("failed to write journal entry")
+		}
+	}
+	return e
+}
+
+// List returns the matching entries, oldest first.
+func (j *Journal) List(f Filter) []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	out := []Entry{}
+	for _, e := range j.entries {
+		if f.matches(e) {
+			out = append(out, e)
+		}
+	}
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[len(out)-f.Limit:]
+	}
+	return out
+}
+
+// Clear drops all in-memory entries and returns how many there were.
+func (j *Journal) Clear() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	n := len(j.entries)
+	j.entries = nil
+	return n
+}
+
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+func (f Filter) matches(e Entry) bool {
+	switch {
+	case f.Method != "" && !strings.EqualFold(f.Method, e.Method):
+		return false
+	case f.Path != "" && f.Path != e.Path:
+		return false
+	case f.SwaggerPath != "" && f.SwaggerPath != e.SwaggerPath:
+		return false
+	case f.Session != "" && f.Session != e.Session:
+		return false
+	case f.Status != 0 && f.Status != e.Response.Status:
+		return false
+	case e.ID <= f.SinceID:
+		return false
+	}
+	return true
+}
+
+// NewMessage captures headers and body. Multiple header values are joined with ", ".
+func NewMessage(status int, header http.Header, body []byte) Message {
+	m := Message{Status: status}
+	if len(header) > 0 {
+		m.Headers = make(map[string]string, len(header))
+		for k, v := range header {
+			m.Headers[strings.ToLower(k)] = strings.Join(v, ", ")
+		}
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return m
+	}
+	if len(body) > MaxBodySize {
+		body = body[:MaxBodySize]
+		m.BodyTruncated = true
+		// Do not turn text into base64 only because the cut split a rune.
+		for i := 0; i < utf8.UTFMax-1 && len(body) > 0 && !utf8.Valid(body); i++ {
+			body = body[:len(body)-1]
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err == nil && !dec.More() {
+			m.Body = v
+			return m
+		}
+	}
+
+	if utf8.Valid(body) {
+		m.Body = string(body)
+	} else {
+		m.Body = base64.StdEncoding.EncodeToString(body)
+		m.BodyEncoding = BodyEncodingBase64
+	}
+	return m
+}
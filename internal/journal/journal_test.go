@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(entries []Entry) []int64 {
+	out := []int64{}
+	for _, e := range entries {
+		out = append(out, e.ID)
+	}
+	return out
+}
+
+func TestJournal_BoundedAndFiltered(t *testing.T) {
+	j, err := NewJournal(Config{Size: 3}, logrus.New())
+	require.NoError(t, err)
+
+	j.Add(Entry{Method: "GET", Path: "/scans/a", SwaggerPath: "/scans/{id}", Response: Message{Status: 200}})
+	j.Add(Entry{Method: "POST", Path: "/scans", SwaggerPath: "/scans", Response: Message{Status: 201}})
+	j.Add(Entry{Method: "GET", Path: "/scans/b", SwaggerPath: "/scans/{id}", Response: Message{Status: 404}})
+	e := j.Add(Entry{Method: "get", Path: "/scans/a", SwaggerPath: "/scans/{id}", Response: Message{Status: 200}})
+	require.Equal(t, int64(4), e.ID)
+
+	require.Equal(t, []int64{2, 3, 4}, ids(j.List(Filter{})), "oldest entry is dropped")
+	require.Equal(t, []int64{3, 4}, ids(j.List(Filter{Method: "GET"})))
+	require.Equal(t, []int64{4}, ids(j.List(Filter{Path: "/scans/a"})))
+	require.Equal(t, []int64{3, 4}, ids(j.List(Filter{SwaggerPath: "/scans/{id}"})))
+	require.Equal(t, []int64{3}, ids(j.List(Filter{Status: 404})))
+	require.Equal(t, []int64{3, 4}, ids(j.List(Filter{SinceID: 2})))
+	require.Equal(t, []int64{4}, ids(j.List(Filter{Limit: 1})))
+
+	require.Equal(t, 3, j.Clear())
+	require.Empty(t, j.List(Filter{}))
+	require.Equal(t, int64(5), j.Add(Entry{}).ID, "ids keep counting after a clear")
+}
+
+func TestJournal_StreamsJSONL(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := NewJournal(Config{File: p}, logrus.New())
+	require.NoError(t, err)
+
+	j.Add(Entry{Method: "GET", Path: "/a"})
+	j.Add(Entry{Method: "POST", Path: "/b"})
+	require.Empty(t, j.List(Filter{}), "size 0 keeps nothing in memory")
+	require.NoError(t, j.Close())
+
+	f, err := os.Open(p)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var paths []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &e))
+		paths = append(paths, e.Path)
+	}
+	require.Equal(t, []string{"/a", "/b"}, paths)
+}
+
+func TestNewMessage(t *testing.T) {
+	m := NewMessage(201, http.Header{"Content-Type": {"application/json"}, "X-Multi": {"a", "b"}}, []byte(`{"n":1}`))
+	require.Equal(t, 201, m.Status)
+	require.Equal(t, map[string]string{"content-type": "application/json", "x-multi": "a, b"}, m.Headers)
+	require.Equal(t, map[string]any{"n": json.Number("1")}, m.Body)
+
+	require.Equal(t, "plain", NewMessage(0, nil, []byte("plain")).Body)
+	require.Nil(t, NewMessage(0, nil, []byte("  ")).Body)
+}
+
+func TestNewMessage_BinaryBodyIsBase64(t *testing.T) {
+	m := NewMessage(0, nil, []byte{0xff, 0x00, 0xfe})
+	require.Equal(t, BodyEncodingBase64, m.BodyEncoding)
+	require.Equal(t, "/wD+", m.Body)
+	require.False(t, m.BodyTruncated)
+}
+
+func TestNewMessage_LargeBodyIsTruncated(t *testing.T) {
+	body := append([]byte(`{"s":"`), bytes.Repeat([]byte("ä"), MaxBodySize)...)
+	m := NewMessage(0, nil, body)
+	require.True(t, m.BodyTruncated)
+	require.Empty(t, m.BodyEncoding)
+	s, ok := m.Body.(string)
+	require.True(t, ok)
+	require.LessOrEqual(t, len(s), MaxBodySize)
+	require.True(t, utf8.ValidString(s))
+}
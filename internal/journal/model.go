@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package journal
+
+import "time"
+
+type Config struct {
+	// Size is the number of entries kept in memory; older entries are dropped.
+	Size int
+	// File, when set, receives every entry as one JSON line.
+	File string
+}
+
+// Entry is one request the emulator answered.
+type Entry struct {
+	ID          int64     `json:"id"`
+	Time        time.Time `json:"time"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	Query       string    `json:"query,omitempty"`
+	SwaggerPath string    `json:"swaggerPath,omitempty"`
+	Session     string    `json:"session,omitempty"`
+
+	// RoutePath is Path without the base path, set when the two differ.
+	RoutePath string `json:"routePath,omitempty"`
+
+	// Source tells how the request was answered: a sample file relative to the
+	// samples dir, or one of the Source* constants.
+	Source string `json:"source,omitempty"`
+	State  string `json:"state,omitempty"`
+
+	Request   Message `json:"request"`
+	Response  Message `json:"response"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+// Message is the captured part of a request or response. Body holds decoded
+// JSON, the raw text when the body is not JSON, or base64 when it is not
+// valid UTF-8. Bodies over MaxBodySize are cut and marked BodyTruncated.
+type Message struct {
+	Status        int               `json:"status,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          any               `json:"body,omitempty"`
+	BodyEncoding  string            `json:"bodyEncoding,omitempty"`
+	BodyTruncated bool              `json:"bodyTruncated,omitempty"`
+}
+
+// Filter selects journal entries. Zero fields match everything.
+type Filter struct {
+	Method      string
+	Path        string
+	SwaggerPath string
+	Session     string
+	Status      int
+	// SinceID only returns entries with a larger id.
+	SinceID int64
+	// Limit keeps the newest entries when more match.
+	Limit int
+}
+
+// MaxBodySize is the number of body bytes kept per message.
+const MaxBodySize = 64 << 10
+
+// BodyEncodingBase64 marks a Message body stored as standard base64.
+const BodyEncodingBase64 = "base64"
+
+const (
+	SourceSpecExample = "openapi_example"
+	SourceUpstream    = "upstream"
+	SourceResources   = "resources"
+)
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+)
+
+// maxNearMisses is how many closest non-matching entries a failed verification reports.
+const maxNearMisses = 3
+
+// Verification counts journal entries that match a request pattern and checks
+// the count against the expectation. Without Count, AtLeast and AtMost it
+// expects at least one match.
+type Verification struct {
+	Method string `json:"method,omitempty"`
+	// Path is a literal request path, or a route template when it contains "{".
+	Path  string               `json:"path,omitempty"`
+	Match samples.VariantMatch `json:"match"`
+
+	Count   *int `json:"count,omitempty"`
+	AtLeast *int `json:"atLeast,omitempty"`
+	AtMost  *int `json:"atMost,omitempty"`
+}
+
+type VerifyResult struct {
+	Pass       bool       `json:"pass"`
+	Count      int        `json:"count"`
+	Expected   string     `json:"expected"`
+	Matched    []int64    `json:"matched"`
+	NearMisses []NearMiss `json:"nearMisses,omitempty"`
+}
+
+// NearMiss is an entry that failed only some of the verification's predicates.
+type NearMiss struct {
+	ID          int64    `json:"id"`
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	SwaggerPath string   `json:"swaggerPath,omitempty"`
+	Mismatches  []string `json:"mismatches"`
+}
+
+func (v Verification) validate() error {
+	if v.Count != nil && (v.AtLeast != nil || v.AtMost != nil) {
+		return errors.New("count cannot be combined with atLeast or atMost")
+	}
+	for name, n := range map[string]*int{"count": v.Count, "atLeast": v.AtLeast, "atMost": v.AtMost} {
+		if n != nil && *n < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	if v.AtLeast != nil && v.AtMost != nil && *v.AtLeast > *v.AtMost {
+		return errors.New("atLeast must not be greater than atMost")
+	}
+	return nil
+}
+
+func (v Verification) bounds() (lo, hi int, expected string) {
+	switch {
+	case v.Count != nil:
+		return *v.Count, *v.Count, fmt.Sprintf("exactly %d", *v.Count)
+	case v.AtLeast != nil && v.AtMost != nil:
+		return *v.AtLeast, *v.AtMost, fmt.Sprintf("between %d and %d", *v.AtLeast, *v.AtMost)
+	case v.AtMost != nil:
+		return 0, *v.AtMost, fmt.Sprintf("at most %d", *v.AtMost)
+	case v.AtLeast != nil:
+		return *v.AtLeast, -1, fmt.Sprintf("at least %d", *v.AtLeast)
+	default:
+		return 1, -1, "at least 1"
+	}
+}
+
+// Verify checks a verification against entries. A failed verification lists the
+// entries with the fewest mismatches as near misses.
+func Verify(entries []Entry, v Verification) (VerifyResult, error) {
+	if err := v.validate(); err != nil {
+		return VerifyResult{}, err
+	}
+	lo, hi, expected := v.bounds()
+
+	res := VerifyResult{Expected: expected, Matched: []int64{}}
+	var misses []NearMiss
+	for _, e := range entries {
+		mm := v.mismatches(e)
+		if len(mm) == 0 {
+			res.Matched = append(res.Matched, e.ID)
+			continue
+		}
+		misses = append(misses, NearMiss{ID: e.ID, Method: e.Method, Path: e.Path, SwaggerPath: e.SwaggerPath, Mismatches: mm})
+	}
+
+	res.Count = len(res.Matched)
+	res.Pass = res.Count >= lo && (hi < 0 || res.Count <= hi)
+	if !res.Pass {
+		sort.SliceStable(misses, func(i, j int) bool { return len(misses[i].Mismatches) < len(misses[j].Mismatches) })
+		if len(misses) > maxNearMisses {
+			misses = misses[:maxNearMisses]
+		}
+		res.NearMisses = misses
+	}
+	return res, nil
+}
+
+func (v Verification) mismatches(e Entry) []string {
+	var out []string
+	if v.Method != "" && !strings.EqualFold(v.Method, e.Method) {
+		out = append(out, fmt.Sprintf("method: want %s, got %s", strings.ToUpper(v.Method), e.Method))
+	}
+	switch {
+	case v.Path == "":
+	case strings.Contains(v.Path, "{"):
+		if v.Path != e.SwaggerPath {
+			out = append(out, fmt.Sprintf("route: want %s, got %s", v.Path, orNone(e.SwaggerPath)))
+		}
+	case v.Path != e.Path && v.Path != e.routed():
+		out = append(out, fmt.Sprintf("path: want %s, got %s", v.Path, e.Path))
+	}
+	return append(out, v.Match.Mismatches(requestData(e))...)
+}
+
+// requestData rebuilds what the variant predicates see from a journal entry.
+func requestData(e Entry) samples.TemplateData {
+	query, _ := url.ParseQuery(e.Query)
+	header := http.Header{}
+	for k, val := range e.Request.Headers {
+		header.Set(k, val)
+	}
+	var body []byte
+	switch b := e.Request.Body.(type) {
+	case nil:
+	case string:
+		body = []byte(b)
+	default:
+		body, _ = json.Marshal(b)
+	}
+	return samples.NewTemplateData(e.Method, e.SwaggerPath, e.routed(), "", &samples.RequestContext{
+		Query:   query,
+		Headers: header,
+		Body:    body,
+	})
+}
+
+// routed is the path relative to the spec's path keys, which the route and its
+// path parameters refer to.
+func (e Entry) routed() string {
+	if e.RoutePath != "" {
+		return e.RoutePath
+	}
+	return e.Path
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
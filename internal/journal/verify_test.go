@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package journal
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func verifyEntries() []Entry {
+	req := func(id int64, method, path, tpl string, body string) Entry {
+		return Entry{
+			ID: id, Method: method, Path: path, SwaggerPath: tpl,
+			Request: NewMessage(0, http.Header{"X-Client": {"scanner"}}, []byte(body)),
+		}
+	}
+	return []Entry{
+		req(1, "POST", "/scans", "/scans", `{"target":{"hosts":["10.0.0.1"]},"ports":22}`),
+		req(2, "DELETE", "/scans/a", "/scans/{id}", ""),
+		req(3, "GET", "/scans/a", "/scans/{id}", ""),
+		req(4, "DELETE", "/scans/b", "/scans/{id}", ""),
+	}
+}
+
+func parseVerification(t *testing.T, raw string) Verification {
+	t.Helper()
+	var v Verification
+	require.NoError(t, json.Unmarshal([]byte(raw), &v))
+	return v
+}
+
+func TestVerify_CountsAndExpectations(t *testing.T) {
+	entries := verifyEntries()
+
+	res, err := Verify(entries, parseVerification(t, `{"method":"delete","path":"/scans/a","count":1}`))
+	require.NoError(t, err)
+	require.True(t, res.Pass)
+	require.Equal(t, []int64{2}, res.Matched)
+	require.Equal(t, "exactly 1", res.Expected)
+	require.Empty(t, res.NearMisses)
+
+	res, err = Verify(entries, parseVerification(t, `{"method":"DELETE","path":"/scans/{id}","atMost":1}`))
+	require.NoError(t, err)
+	require.False(t, res.Pass)
+	require.Equal(t, 2, res.Count)
+
+	res, err = Verify(entries, parseVerification(t, `{"method":"DELETE","match":{"path":{"id":"b"}},"atLeast":1,"atMost":2}`))
+	require.NoError(t, err)
+	require.True(t, res.Pass)
+	require.Equal(t, []int64{4}, res.Matched)
+
+	res, err = Verify(entries, parseVerification(t, `{
+	  "method":"POST","path":"/scans",
+	  "match":{"headers":{"x-client":"scanner"},"body":{"$.target.hosts[0]":{"regex":"^10\\."},"$.ports":22}}
+	}`))
+	require.NoError(t, err)
+	require.True(t, res.Pass, "numbers from the journal compare with JSON numbers")
+}
+
+func TestVerify_NearMisses(t *testing.T) {
+	res, err := Verify(verifyEntries(), parseVerification(t, `{"method":"DELETE","path":"/scans/c","count":1}`))
+	require.NoError(t, err)
+	require.False(t, res.Pass)
+	require.Equal(t, 0, res.Count)
+	require.Len(t, res.NearMisses, 3)
+
+	// the two DELETEs only miss the path, so they come first
+	require.Equal(t, int64(2), res.NearMisses[0].ID)
+	require.Equal(t, []string{"path: want /scans/c, got /scans/a"}, res.NearMisses[0].Mismatches)
+	require.Equal(t, int64(4), res.NearMisses[1].ID)
+	require.Len(t, res.NearMisses[2].Mismatches, 2)
+}
+
+func TestVerify_RejectsConflictingExpectations(t *testing.T) {
+	for _, raw := range []string{
+		`{"count":1,"atLeast":1}`,
+		`{"atLeast":3,"atMost":1}`,
+		`{"count":-1}`,
+	} {
+		_, err := Verify(nil, parseVerification(t, raw))
+		require.Error(t, err, raw)
+	}
+
+	res, err := Verify(nil, Verification{})
+	require.NoError(t, err)
+	require.False(t, res.Pass, "default expectation is at least one call")
+	require.Equal(t, "at least 1", res.Expected)
+}
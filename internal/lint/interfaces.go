@@ -0,0 +1,9 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package lint
+
+type ILinter interface {
+	Run() ([]Finding, error)
+}
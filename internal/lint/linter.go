@@ -0,0 +1,380 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+)
+
+var httpMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true,
+	"DELETE": true, "OPTIONS": true, "TRACE": true, "CONNECT": true,
+}
+
+// Linter checks a sample tree against the routes of a spec without starting the server.
+type Linter struct {
+	cfg    Config
+	spec   openapi.ISpecProvider
+	routes openapi.IRouterProvider
+}
+
+// sampleFile is a folder-layout sample waiting for the scenario reachability check.
+type sampleFile struct {
+	rel     string
+	dir     string
+	name    string
+	state   string
+	route   string
+	variant bool
+}
+
+func NewLinter(cfg Config, spec openapi.ISpecProvider, routes openapi.IRouterProvider) ILinter {
+	if strings.TrimSpace(string(cfg.Layout)) == "" {
+		cfg.Layout = config.LayoutAuto
+	}
+	if strings.TrimSpace(cfg.ScenarioFilename) == "" {
+		cfg.ScenarioFilename = "scenario.json"
+	}
+	return &Linter{cfg: cfg, spec: spec, routes: routes}
+}
+
+func (l *Linter) Run() ([]Finding, error) {
+	st, err := os.Stat(l.cfg.SamplesDir)
+	if err != nil {
+		return nil, fmt.Errorf("samples dir: %w", err)
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("samples dir %s is not a directory", l.cfg.SamplesDir)
+	}
+
+	var routes []openapi.Route
+	if l.routes != nil {
+		routes = l.routes.GetRoutes()
+	}
+
+	byMethodPath := map[string]openapi.Route{}
+	byFlatName := map[string]openapi.Route{}
+	knownPaths := map[string]bool{}
+	for _, r := range routes {
+		byMethodPath[routeKey(r.Method, r.Swagger)] = r
+		byFlatName[r.SampleFile] = r
+		knownPaths[r.Swagger] = true
+	}
+
+	foldersEnabled := l.cfg.Layout == config.LayoutAuto || l.cfg.Layout == config.LayoutFolders
+	flatEnabled := l.cfg.Layout == config.LayoutAuto || l.cfg.Layout == config.LayoutFlat
+
+	var out []Finding
+	covered := map[string]bool{}
+	scenarioRefs := map[string]map[string]bool{}    // dir -> referenced files
+	variantSets := map[string]*samples.VariantSet{} // dir -> parsed variants.json, nil when broken
+	bodyFiles := map[string]bool{}                  // files referenced as an envelope bodyFile
+	var pending []sampleFile
+	var extras []sampleFile // files that are no samples, fine when referenced
+
+	// checkSample checks a sample's content and records the file its envelope points to.
+	checkSample := func(p, rel string) (Finding, bool) {
+		f, ok := checkContent(p, rel, l.cfg.Templates)
+		if !ok {
+			return f, false
+		}
+		ref := samples.EnvelopeBodyFile(p)
+		if ref == "" {
+			return f, true
+		}
+		target := path.Join(path.Dir(rel), filepath.ToSlash(ref))
+		if target == ".." || strings.HasPrefix(target, "../") {
+			return Finding{Kind: KindMalformedEnvelope, File: rel, Message: fmt.Sprintf("body file %q is outside the samples dir", ref)}, false
+		}
+		if _, err := os.Stat(filepath.Join(l.cfg.SamplesDir, filepath.FromSlash(target))); err != nil {
+			return Finding{Kind: KindMalformedEnvelope, File: rel, Message: fmt.Sprintf("body file %q does not exist", ref)}, false
+		}
+		bodyFiles[target] = true
+		return f, true
+	}
+
+	// variantFor returns the variant of dir that serves name, loading variants.json once per folder.
+	variantFor := func(dir, name string) *samples.Variant {
+		vs, seen := variantSets[dir]
+		if !seen {
+			vs, _ = samples.LoadVariants(filepath.Join(dir, samples.VariantsFilename))
+			variantSets[dir] = vs
+		}
+		if vs == nil {
+			return nil
+		}
+		for i := range vs.Variants {
+			if path.Clean(filepath.ToSlash(vs.Variants[i].File)) == name {
+				return &vs.Variants[i]
+			}
+		}
+		return nil
+	}
+
+	err = filepath.WalkDir(l.cfg.SamplesDir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.cfg.SamplesDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		dir, name := path.Split(rel)
+		swaggerDir := "/" + strings.TrimSuffix(dir, "/")
+
+		// legacy flat file: METHOD__path.json at the root
+		if dir == "" && strings.Contains(name, "__") && strings.HasSuffix(name, ".json") {
+			method := strings.SplitN(name, "__", 2)[0]
+			if !httpMethods[method] {
+				out = append(out, Finding{Kind: KindUnknownMethod, File: rel, Message: fmt.Sprintf("unknown HTTP method %q", method)})
+				return nil
+			}
+			r, ok := byFlatName[name]
+			if !ok {
+				out = append(out, Finding{Kind: KindOrphanFile, File: rel, Message: "flat sample does not match any route in the spec"})
+				return nil
+			}
+			if !flatEnabled {
+				out = append(out, Finding{Kind: KindOrphanFile, File: rel, Route: routeKey(r.Method, r.Swagger), Message: fmt.Sprintf("flat samples are ignored with LAYOUT_MODE=%s", l.cfg.Layout)})
+				return nil
+			}
+			if f, ok := checkSample(p, rel); !ok {
+				f.Route = routeKey(r.Method, r.Swagger)
+				out = append(out, f)
+				return nil
+			}
+			covered[routeKey(r.Method, r.Swagger)] = true
+			return nil
+		}
+
+		if l.cfg.ScenarioEnabled && name == l.cfg.ScenarioFilename {
+			if !knownPaths[swaggerDir] {
+				out = append(out, Finding{Kind: KindOrphanFile, File: rel, Message: fmt.Sprintf("scenario folder %s does not match any path in the spec", swaggerDir)})
+				return nil
+			}
+			sc, err := samples.LoadScenario(p)
+			if err != nil {
+				out = append(out, Finding{Kind: KindInvalidScenario, File: rel, Message: err.Error()})
+				return nil
+			}
+			refs := map[string]bool{}
+			for _, f := range scenarioFiles(sc) {
+				refs[f] = true
+				if _, err := os.Stat(filepath.Join(filepath.Dir(p), filepath.FromSlash(f))); err != nil {
+					out = append(out, Finding{Kind: KindInvalidScenario, File: rel, Message: fmt.Sprintf("referenced file %q does not exist", f)})
+				}
+			}
+			scenarioRefs[swaggerDir] = refs
+			for _, r := range routes {
+				if r.Swagger == swaggerDir {
+					covered[routeKey(r.Method, r.Swagger)] = true
+				}
+			}
+			return nil
+		}
+
+		if name == samples.VariantsFilename {
+			out = append(out, l.checkVariants(p, rel, swaggerDir, knownPaths, byMethodPath, foldersEnabled)...)
+			return nil
+		}
+
+		ext := path.Ext(name)
+
+		// plain <METHOD>.json files stay the fallback even when a variant points at them
+		if v := variantFor(filepath.Dir(p), name); v != nil && !httpMethods[strings.TrimSuffix(name, ext)] {
+			r, ok := byMethodPath[routeKey(v.Method, swaggerDir)]
+			if !ok || !foldersEnabled {
+				// reported on variants.json
+				return nil
+			}
+			rk := routeKey(r.Method, r.Swagger)
+			if f, ok := checkSample(p, rel); !ok {
+				f.Route = rk
+				out = append(out, f)
+				return nil
+			}
+			pending = append(pending, sampleFile{rel: rel, dir: swaggerDir, name: name, route: rk, variant: true})
+			return nil
+		}
+
+		method, state, _ := strings.Cut(strings.TrimSuffix(name, ext), ".")
+		if ext != ".json" && (!samples.IsSampleExtension(ext) || !httpMethods[method]) {
+			// e.g. a PDF served through a bodyFile
+			extras = append(extras, sampleFile{rel: rel, dir: swaggerDir, name: name})
+			return nil
+		}
+		if strings.HasPrefix(name, "scenario") {
+			out = append(out, Finding{Kind: KindOrphanFile, File: rel, Message: fmt.Sprintf("scenario file is not used (SCENARIO_FILENAME=%s)", l.cfg.ScenarioFilename)})
+			return nil
+		}
+
+		if !httpMethods[method] {
+			out = append(out, Finding{Kind: KindUnknownMethod, File: rel, Message: fmt.Sprintf("unknown HTTP method %q", method)})
+			return nil
+		}
+
+		r, ok := byMethodPath[routeKey(method, swaggerDir)]
+		if !ok {
+			out = append(out, Finding{Kind: KindOrphanFile, File: rel, Message: fmt.Sprintf("no operation %s %s in the spec", method, swaggerDir)})
+			return nil
+		}
+		rk := routeKey(r.Method, r.Swagger)
+		if !foldersEnabled {
+			out = append(out, Finding{Kind: KindOrphanFile, File: rel, Route: rk, Message: fmt.Sprintf("folder samples are ignored with LAYOUT_MODE=%s", l.cfg.Layout)})
+			return nil
+		}
+		if f, ok := checkSample(p, rel); !ok {
+			f.Route = rk
+			out = append(out, f)
+			return nil
+		}
+
+		pending = append(pending, sampleFile{rel: rel, dir: swaggerDir, name: name, state: state, route: rk})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk samples dir: %w", err)
+	}
+
+	for _, f := range extras {
+		if !bodyFiles[f.rel] && !scenarioRefs[f.dir][f.name] {
+			out = append(out, Finding{Kind: KindOrphanFile, File: f.rel, Message: "not a sample file and not referenced as a body file"})
+		}
+	}
+
+	for _, f := range pending {
+		refs, hasScenario := scenarioRefs[f.dir]
+		switch {
+		case samples.IsStatusState(f.state):
+			// served when a request prefers that status code, scenario or not
+		case hasScenario && !refs[f.name]:
+			out = append(out, Finding{Kind: KindUnreachableState, File: f.rel, Route: f.route, Message: fmt.Sprintf("not referenced by %s", l.cfg.ScenarioFilename)})
+		case f.variant:
+			// served when its predicates match; the route still needs a plain sample or example
+		case !hasScenario && f.state != "":
+			out = append(out, Finding{Kind: KindUnreachableState, File: f.rel, Route: f.route, Message: fmt.Sprintf("state %q is only served through a %s", f.state, l.cfg.ScenarioFilename)})
+		case !hasScenario:
+			covered[f.route] = true
+		}
+	}
+
+	for _, r := range routes {
+		rk := routeKey(r.Method, r.Swagger)
+		if covered[rk] {
+			continue
+		}
+		if l.spec != nil && l.spec.HasExample(r.Swagger, r.Method) {
+			continue
+		}
+		out = append(out, Finding{Kind: KindMissingSample, Route: rk, Message: "no sample file and no spec example"})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].File != out[j].File {
+			return out[i].File < out[j].File
+		}
+		if out[i].Route != out[j].Route {
+			return out[i].Route < out[j].Route
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out, nil
+}
+
+// checkVariants validates a variants.json file against the spec and its folder.
+func (l *Linter) checkVariants(p, rel, swaggerDir string, knownPaths map[string]bool, byMethodPath map[string]openapi.Route, foldersEnabled bool) []Finding {
+	if !foldersEnabled {
+		return []Finding{{Kind: KindOrphanFile, File: rel, Message: fmt.Sprintf("variants are ignored with LAYOUT_MODE=%s", l.cfg.Layout)}}
+	}
+	if !knownPaths[swaggerDir] {
+		return []Finding{{Kind: KindOrphanFile, File: rel, Message: fmt.Sprintf("variants folder %s does not match any path in the spec", swaggerDir)}}
+	}
+	if l.cfg.ScenarioEnabled {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(p), l.cfg.ScenarioFilename)); err == nil {
+			return []Finding{{Kind: KindUnreachableState, File: rel, Message: fmt.Sprintf("variants are not used while %s is present", l.cfg.ScenarioFilename)}}
+		}
+	}
+
+	vs, err := samples.LoadVariants(p)
+	if err != nil {
+		return []Finding{{Kind: KindInvalidVariants, File: rel, Message: err.Error()}}
+	}
+
+	var out []Finding
+	for i, v := range vs.Variants {
+		if _, ok := byMethodPath[routeKey(v.Method, swaggerDir)]; !ok {
+			out = append(out, Finding{Kind: KindInvalidVariants, File: rel, Message: fmt.Sprintf("variant %d: no operation %s %s in the spec", i, v.Method, swaggerDir)})
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(p), filepath.FromSlash(v.File))); err != nil {
+			out = append(out, Finding{Kind: KindInvalidVariants, File: rel, Message: fmt.Sprintf("variant %d: referenced file %q does not exist", i, v.File)})
+		}
+	}
+	return out
+}
+
+func checkContent(p, rel string, templates bool) (Finding, bool) {
+	b, err := os.ReadFile(p) // #nosec G304 -- walking the configured samples dir
+	if err != nil {
+		return Finding{Kind: KindInvalidJSON, File: rel, Message: err.Error()}, false
+	}
+
+	if !samples.IsJSONContentType(samples.ContentTypeForFile(p)) {
+		// served as they are; only text files are templated
+		if templates && samples.IsTextContentType(samples.ContentTypeForFile(p)) {
+			if _, err := samples.RenderTemplate(rel, b, samples.NewTemplateData("", "", "", "", nil), nil); err != nil {
+				return Finding{Kind: KindInvalidTemplate, File: rel, Message: err.Error()}, false
+			}
+		}
+		return Finding{}, true
+	}
+
+	if templates {
+		b, err = samples.RenderTemplate(rel, b, samples.NewTemplateData("", "", "", "", nil), nil)
+		if err != nil {
+			return Finding{Kind: KindInvalidTemplate, File: rel, Message: err.Error()}, false
+		}
+	}
+
+	err = samples.CheckSample(b)
+	switch {
+	case err == nil:
+		return Finding{}, true
+	case errors.Is(err, samples.ErrInvalidSampleJSON):
+		return Finding{Kind: KindInvalidJSON, File: rel, Message: err.Error()}, false
+	default:
+		return Finding{Kind: KindMalformedEnvelope, File: rel, Message: err.Error()}, false
+	}
+}
+
+func scenarioFiles(sc *samples.Scenario) []string {
+	var out []string
+	for _, e := range sc.Sequence {
+		out = append(out, e.File)
+	}
+	for _, e := range sc.Timeline {
+		out = append(out, e.File)
+	}
+	return out
+}
+
+func routeKey(method, swaggerPath string) string {
+	return strings.ToUpper(method) + " " + swaggerPath
+}
@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+)
+
+const lintSpec = `{
+  "openapi":"3.0.3",
+  "info":{"title":"t","version":"1"},
+  "paths":{
+	"/scans":{
+	  "post":{"responses":{"201":{"description":"created","content":{"application/json":{"schema":{"type":"string"}}}}}}
+	},
+	"/scans/{id}":{
+	  "get":{"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"type":"object"}}}}}},
+	  "delete":{"responses":{"204":{"description":"deleted"}}}
+	},
+	"/scans/{id}/status":{
+	  "get":{"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"type":"object"}}}}}}
+	},
+	"/vts":{
+	  "get":{"responses":{"200":{"description":"ok","content":{"application/json":{"example":["1.2.3"]}}}}}
+	},
+	"/health":{
+	  "get":{"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"type":"object"}}}}}}
+	}
+  }
+}`
+
+func newTestLinter(t *testing.T, dir string, layout config.LayoutMode) ILinter {
+	t.Helper()
+
+	specPath := filepath.Join(t.TempDir(), "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(lintSpec), 0o600))
+
+	spec, err := openapi.NewSpecProvider(specPath, logrus.New())
+	require.NoError(t, err)
+
+	return NewLinter(Config{
+		SamplesDir:       dir,
+		Layout:           layout,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+	}, spec, openapi.NewRouterProvider(spec.GetSpec()))
+}
+
+func writeSample(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+}
+
+func kindsByFile(findings []Finding) map[string]Kind {
+	out := map[string]Kind{}
+	for _, f := range findings {
+		key := f.File
+		if key == "" {
+			key = f.Route
+		}
+		out[key] = f.Kind
+	}
+	return out
+}
+
+func TestLinter_CleanTree(t *testing.T) {
+	dir := t.TempDir()
+	writeSample(t, dir, "scans/POST.json", `{"status":201,"body":"abc"}`)
+	writeSample(t, dir, "scans/{id}/GET.json", `{"id":"abc","status":"running"}`)
+	writeSample(t, dir, "scans/{id}/status/scenario.json", `{
+	  "version":1,"mode":"step","key":{"pathParam":"id"},
+	  "sequence":[{"state":"requested","file":"GET.requested.json"},{"state":"done","file":"GET.done.json"}],
+	  "behavior":{"advanceOn":[{"method":"GET"}]}
+	}`)
+	writeSample(t, dir, "scans/{id}/status/GET.requested.json", `{"status":"requested"}`)
+	writeSample(t, dir, "scans/{id}/status/GET.done.json", `{"status":"done"}`)
+	writeSample(t, dir, "scans/{id}/GET.status-404.json", `{"status":404,"body":{"error":"not found"}}`)
+	writeSample(t, dir, "scans/{id}/status/GET.status-404.json", `{"status":404,"body":{}}`)
+	writeSample(t, dir, "GET__health.json", `{}`)
+	writeSample(t, dir, ".gitkeep", ``)
+
+	findings, err := newTestLinter(t, dir, config.LayoutAuto).Run()
+	require.NoError(t, err)
+	require.Empty(t, findings)
+}
+
+func TestLinter_ReportsEveryProblemKind(t *testing.T) {
+	dir := t.TempDir()
+	writeSample(t, dir, "scans/POST.json", `{"status":201,"body":"abc"`)
+	writeSample(t, dir, "scans/{id}/GET.json", `{"status":"201","headers":{}}`)
+	writeSample(t, dir, "scans/{id}/FETCH.json", `{}`)
+	writeSample(t, dir, "scans/{id}/PUT.json", `{}`)
+	writeSample(t, dir, "scans/{id}/GET.running.json", `{}`)
+	writeSample(t, dir, "scans/{id}/status/scenario.json", `{
+	  "version":1,"mode":"step","key":{"pathParam":"id"},
+	  "sequence":[{"state":"requested","file":"GET.requested.json"}]
+	}`)
+	writeSample(t, dir, "scans/{id}/status/GET.stale.json", `{}`)
+	writeSample(t, dir, "scans/{id}/status/scenario.time.json", `{}`)
+	writeSample(t, dir, "GET__nothing.json", `{}`)
+	writeSample(t, dir, "notes.txt", `hello`)
+
+	findings, err := newTestLinter(t, dir, config.LayoutAuto).Run()
+	require.NoError(t, err)
+
+	got := kindsByFile(findings)
+	require.Equal(t, map[string]Kind{
+		"scans/POST.json":                      KindInvalidJSON,
+		"scans/{id}/GET.json":                  KindMalformedEnvelope,
+		"scans/{id}/FETCH.json":                KindUnknownMethod,
+		"scans/{id}/PUT.json":                  KindOrphanFile,
+		"scans/{id}/GET.running.json":          KindUnreachableState,
+		"scans/{id}/status/scenario.json":      KindInvalidScenario,
+		"scans/{id}/status/GET.stale.json":     KindUnreachableState,
+		"scans/{id}/status/scenario.time.json": KindOrphanFile,
+		"GET__nothing.json":                    KindOrphanFile,
+		"notes.txt":                            KindOrphanFile,
+		"POST /scans":                          KindMissingSample,
+		"GET /scans/{id}":                      KindMissingSample,
+		"GET /health":                          KindMissingSample,
+	}, got)
+}
+
+func TestLinter_NonJSONSamples(t *testing.T) {
+	dir := t.TempDir()
+	writeSample(t, dir, "scans/{id}/GET.xml", `<scan/>`)
+	writeSample(t, dir, "scans/{id}/GET.status-404.txt", `not found`)
+	writeSample(t, dir, "scans/{id}/DELETE.json", `{"status":200,"bodyFile":"report.pdf"}`)
+	writeSample(t, dir, "scans/{id}/report.pdf", "%PDF-1.4")
+	writeSample(t, dir, "scans/{id}/old.pdf", "%PDF-1.4")
+	writeSample(t, dir, "scans/POST.json", `{"status":201,"bodyFile":"missing.bin"}`)
+	writeSample(t, dir, "vts/GET.json", `{"bodyFile":"../../etc/passwd"}`)
+
+	findings, err := newTestLinter(t, dir, config.LayoutFolders).Run()
+	require.NoError(t, err)
+
+	got := kindsByFile(findings)
+	require.Equal(t, KindOrphanFile, got["scans/{id}/old.pdf"])
+	require.Equal(t, KindMalformedEnvelope, got["scans/POST.json"])
+	require.Equal(t, KindMalformedEnvelope, got["vts/GET.json"])
+	require.NotContains(t, got, "scans/{id}/GET.xml")
+	require.NotContains(t, got, "scans/{id}/GET.status-404.txt")
+	require.NotContains(t, got, "scans/{id}/report.pdf")
+	require.NotContains(t, got, "GET /scans/{id}")
+	require.NotContains(t, got, "DELETE /scans/{id}")
+}
+
+func TestLinter_LayoutModeIgnoresOtherLayout(t *testing.T) {
+	dir := t.TempDir()
+	writeSample(t, dir, "GET__health.json", `{}`)
+	writeSample(t, dir, "scans/{id}/GET.json", `{}`)
+
+	findings, err := newTestLinter(t, dir, config.LayoutFolders).Run()
+	require.NoError(t, err)
+	got := kindsByFile(findings)
+	require.Equal(t, KindOrphanFile, got["GET__health.json"])
+	require.Equal(t, KindMissingSample, got["GET /health"])
+	require.NotContains(t, got, "scans/{id}/GET.json")
+
+	findings, err = newTestLinter(t, dir, config.LayoutFlat).Run()
+	require.NoError(t, err)
+	got = kindsByFile(findings)
+	require.Equal(t, KindOrphanFile, got["scans/{id}/GET.json"])
+	require.NotContains(t, got, "GET__health.json")
+}
+
+func TestLinter_MissingSamplesDir(t *testing.T) {
+	_, err := newTestLinter(t, filepath.Join(t.TempDir(), "nope"), config.LayoutAuto).Run()
+	require.Error(t, err)
+}
+
+func TestLinter_Templates(t *testing.T) {
+	dir := t.TempDir()
+	writeSample(t, dir, "scans/POST.json", `{"status":201,"body":{{ json (uuid) }}}`)
+	writeSample(t, dir, "scans/{id}/GET.json", `{"id":{{ json .Path.id }}`)
+	writeSample(t, dir, "health/GET.json", `{"ok":{{ .Nope }</`)
+
+	specPath := filepath.Join(t.TempDir(), "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(lintSpec), 0o600))
+	spec, err := openapi.NewSpecProvider(specPath, logrus.New())
+	require.NoError(t, err)
+
+	findings, err := NewLinter(Config{
+		SamplesDir: dir,
+		Layout:     config.LayoutFolders,
+		Templates:  true,
+	}, spec, openapi.NewRouterProvider(spec.GetSpec())).Run()
+	require.NoError(t, err)
+
+	got := kindsByFile(findings)
+	require.NotContains(t, got, "scans/POST.json")
+	require.Equal(t, KindInvalidJSON, got["scans/{id}/GET.json"])
+	require.Equal(t, KindInvalidTemplate, got["health/GET.json"])
+}
+
+func TestLinter_Variants(t *testing.T) {
+	dir := t.TempDir()
+	writeSample(t, dir, "scans/{id}/GET.json", `{}`)
+	writeSample(t, dir, "scans/{id}/variants.json", `{
+	  "version":1,
+	  "variants":[
+		{"method":"GET","match":{"query":{"details":"true"}},"file":"GET.details.json"},
+		{"method":"GET","match":{"headers":{"x-role":"admin"}},"file":"admin.json"},
+		{"method":"PUT","match":{},"file":"GET.json"},
+		{"method":"DELETE","match":{},"file":"DELETE.gone.json"}
+	  ]
+	}`)
+	writeSample(t, dir, "scans/{id}/GET.details.json", `{"details":true}`)
+	writeSample(t, dir, "scans/{id}/admin.json", `{"admin":`)
+	writeSample(t, dir, "vts/variants.json", `{"version":2,"variants":[]}`)
+	writeSample(t, dir, "nothing/variants.json", `{"version":1,"variants":[]}`)
+
+	findings, err := newTestLinter(t, dir, config.LayoutAuto).Run()
+	require.NoError(t, err)
+
+	var variantMsgs []string
+	for _, f := range findings {
+		if f.File == "scans/{id}/variants.json" {
+			require.Equal(t, KindInvalidVariants, f.Kind)
+			variantMsgs = append(variantMsgs, f.Message)
+		}
+	}
+	require.Len(t, variantMsgs, 2)
+	require.Contains(t, variantMsgs[0]+variantMsgs[1], "no operation PUT /scans/{id}")
+	require.Contains(t, variantMsgs[0]+variantMsgs[1], `"DELETE.gone.json" does not exist`)
+
+	got := kindsByFile(findings)
+	require.NotContains(t, got, "scans/{id}/GET.details.json", "variant files are reachable")
+	require.Equal(t, KindInvalidJSON, got["scans/{id}/admin.json"])
+	require.Equal(t, KindInvalidVariants, got["vts/variants.json"])
+	require.Equal(t, KindOrphanFile, got["nothing/variants.json"])
+}
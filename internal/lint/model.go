@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package lint
+
+import "github.com/greenbone/gvm-openapi-emulator/config"
+
+type Kind string
+
+const (
+	KindOrphanFile        Kind = "orphan_file"
+	KindInvalidJSON       Kind = "invalid_json"
+	KindMalformedEnvelope Kind = "malformed_envelope"
+	KindUnknownMethod     Kind = "unknown_method"
+	KindUnreachableState  Kind = "unreachable_state"
+	KindInvalidScenario   Kind = "invalid_scenario"
+	KindMissingSample     Kind = "missing_sample"
+	KindInvalidTemplate   Kind = "invalid_template"
+	KindInvalidVariants   Kind = "invalid_variants"
+)
+
+// Finding is a single problem in a sample tree.
+// File is relative to the samples directory; Route is "<METHOD> <swagger path>".
+type Finding struct {
+	Kind    Kind   `json:"kind"`
+	File    string `json:"file,omitempty"`
+	Route   string `json:"route,omitempty"`
+	Message string `json:"message"`
+}
+
+type Config struct {
+	SamplesDir       string
+	Layout           config.LayoutMode
+	ScenarioEnabled  bool
+	ScenarioFilename string
+
+	// Templates renders sample files with empty request data before checking them.
+	Templates bool
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxServerURLs caps the number of URLs one server expands to through enum variables.
+const maxServerURLs = 64
+
+// BasePaths returns the base paths the spec declares: Swagger 2 basePath, or the
+// path of every OpenAPI 3 server URL with each combination of its variable values.
+// They are normalized to "/a/b" without a trailing slash, longest first; the root
+// path is left out.
+func BasePaths(spec *Spec) []string {
+	if spec == nil {
+		return nil
+	}
+	if spec.Doc2 != nil {
+		return NormalizeBasePaths([]string{spec.Doc2.BasePath})
+	}
+	if spec.Doc3 == nil {
+		return nil
+	}
+
+	var raw []string
+	for _, srv := range spec.Doc3.Servers {
+		if srv == nil {
+			continue
+		}
+		for _, u := range expandServerURL(srv.URL, srv.Variables) {
+			raw = append(raw, serverURLPath(u))
+		}
+	}
+	return NormalizeBasePaths(raw)
+}
+
+// NormalizeBasePaths cleans, dedupes and sorts base paths longest first.
+func NormalizeBasePaths(paths []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, p := range paths {
+		p = strings.Trim(strings.TrimSpace(p), "/")
+		if p == "" || p == "." || strings.Contains(p, "{") {
+			continue
+		}
+		p = "/" + strings.TrimPrefix(p, "./")
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i]) > len(out[j]) })
+	return out
+}
+
+// expandServerURL substitutes server variables; every enum value yields its own URL.
+func expandServerURL(u string, vars map[string]*openapi3.ServerVariable) []string {
+	out := []string{u}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := vars[name]
+		if v == nil {
+			continue
+		}
+		values := v.Enum
+		if len(values) == 0 {
+			values = []string{v.Default}
+		}
+
+		var next []string
+		for _, cur := range out {
+			for _, val := range values {
+				if len(next) < maxServerURLs {
+					next = append(next, strings.ReplaceAll(cur, "{"+name+"}", val))
+				}
+			}
+		}
+		out = next
+	}
+	return out
+}
+
+// serverURLPath drops scheme and host from an absolute server URL.
+func serverURLPath(u string) string {
+	if _, rest, ok := strings.Cut(u, "://"); ok {
+		if i := strings.Index(rest, "/"); i >= 0 {
+			return rest[i:]
+		}
+		return ""
+	}
+	if strings.HasPrefix(u, "//") {
+		rest := strings.TrimPrefix(u, "//")
+		if i := strings.Index(rest, "/"); i >= 0 {
+			return rest[i:]
+		}
+		return ""
+	}
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	return u
+}
+
+// StripBasePath removes the longest matching base path from path. The returned path
+// is relative to the spec's path keys. Specs whose path keys already start with the
+// base path keep the path as is when only the unstripped path has a route. When no
+// base path matches, ok reports whether the path may be used as is, which require
+// mode forbids.
+func (p *RouterProvider) StripBasePath(path string) (string, bool) {
+	for _, base := range p.basePaths {
+		if rest, ok := strings.CutPrefix(path, base); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+			if rest == "" {
+				rest = "/"
+			}
+			if len(p.AllowedMethods(rest)) == 0 && len(p.AllowedMethods(path)) > 0 {
+				return path, true
+			}
+			return rest, true
+		}
+	}
+	return path, !p.requireBase || len(p.basePaths) == 0
+}
+
+func (p *RouterProvider) BasePaths() []string {
+	return p.basePaths
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestBasePaths_OpenAPI3Servers(t *testing.T) {
+	doc := &openapi3.T{Servers: openapi3.Servers{
+		{URL: "https://scanner.example.com/api/v1/"},
+		{URL: "/api/{version}", Variables: map[string]*openapi3.ServerVariable{
+			"version": {Default: "v2", Enum: []string{"v2", "v3"}},
+		}},
+		{URL: "{scheme}://{host}/gmp", Variables: map[string]*openapi3.ServerVariable{
+			"scheme": {Default: "https"},
+			"host":   {Default: "localhost"},
+		}},
+		{URL: "/"},
+		{URL: "/api/v1"},
+	}}
+
+	got := BasePaths(&Spec{Doc3: doc})
+	want := []string{"/api/v1", "/api/v2", "/api/v3", "/gmp"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBasePaths_Swagger2AndNil(t *testing.T) {
+	got := BasePaths(&Spec{Doc2: &openapi2.T{BasePath: "/scanner/"}, Doc3: &openapi3.T{}})
+	if !reflect.DeepEqual(got, []string{"/scanner"}) {
+		t.Fatalf("unexpected base paths: %v", got)
+	}
+	if got := BasePaths(&Spec{Doc2: &openapi2.T{BasePath: "/"}}); len(got) != 0 {
+		t.Fatalf("expected root base path to be dropped, got %v", got)
+	}
+	if BasePaths(nil) != nil {
+		t.Fatalf("expected nil for nil spec")
+	}
+}
+
+func TestRouterProvider_StripBasePath(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/scans/{id}", &openapi3.PathItem{Get: &openapi3.Operation{Responses: openapi3.NewResponses()}})
+	spec := &Spec{Doc3: &openapi3.T{
+		Paths:   paths,
+		Servers: openapi3.Servers{{URL: "/api"}, {URL: "/api/v1"}},
+	}}
+
+	cases := []struct {
+		base    BaseConfig
+		path    string
+		want    string
+		wantOK  bool
+		comment string
+	}{
+		{BaseConfig{}, "/api/v1/scans/1", "/scans/1", true, "longest base wins"},
+		{BaseConfig{}, "/api/scans/1", "/scans/1", true, ""},
+		{BaseConfig{}, "/api", "/", true, "base path alone is the root"},
+		{BaseConfig{}, "/apiv1/scans/1", "/apiv1/scans/1", true, "only whole segments match"},
+		{BaseConfig{}, "/scans/1", "/scans/1", true, "base is optional by default"},
+		{BaseConfig{Require: true}, "/scans/1", "/scans/1", false, "require mode"},
+		{BaseConfig{Require: true}, "/api/v1/scans/1", "/scans/1", true, ""},
+		{BaseConfig{Paths: []string{"gmp/"}}, "/gmp/scans/1", "/scans/1", true, "override replaces the spec"},
+		{BaseConfig{Paths: []string{"gmp/"}}, "/api/scans/1", "/api/scans/1", true, ""},
+	}
+	for _, tc := range cases {
+		rp := NewRouterProviderWithBase(spec, tc.base)
+		got, ok := rp.StripBasePath(tc.path)
+		if got != tc.want || ok != tc.wantOK {
+			t.Fatalf("%s %s: expected %q %v, got %q %v", tc.comment, tc.path, tc.want, tc.wantOK, got, ok)
+		}
+	}
+}
+
+func TestRouterProvider_StripBasePath_PathKeysWithBase(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/api/v1/scans/{id}", &openapi3.PathItem{Get: &openapi3.Operation{Responses: openapi3.NewResponses()}})
+	paths.Set("/health", &openapi3.PathItem{Get: &openapi3.Operation{Responses: openapi3.NewResponses()}})
+	spec := &Spec{Doc3: &openapi3.T{
+		Paths:   paths,
+		Servers: openapi3.Servers{{URL: "/api/v1"}},
+	}}
+
+	for _, base := range []BaseConfig{{}, {Require: true}} {
+		rp := NewRouterProviderWithBase(spec, base)
+
+		got, ok := rp.StripBasePath("/api/v1/scans/1")
+		if got != "/api/v1/scans/1" || !ok {
+			t.Fatalf("expected the unstripped path to be kept, got %q %v", got, ok)
+		}
+		if rt := rp.FindRoute("GET", got); rt == nil || rt.Swagger != "/api/v1/scans/{id}" {
+			t.Fatalf("expected a route for %q, got %+v", got, rt)
+		}
+
+		if got, ok := rp.StripBasePath("/api/v1/health"); got != "/health" || !ok {
+			t.Fatalf("expected the stripped path when it has a route, got %q %v", got, ok)
+		}
+	}
+}
@@ -0,0 +1,658 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// formatSamples are plausible values for the string formats clients tend to parse.
+var formatSamples = map[string]string{
+	"date-time":     "2026-01-28T00:00:00Z",
+	"date":          "2026-01-28",
+	"time":          "00:00:00Z",
+	"duration":      "P1D",
+	"uuid":          "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"email":         "user@example.com",
+	"idn-email":     "user@example.com",
+	"hostname":      "example.com",
+	"idn-hostname":  "example.com",
+	"ipv4":          "192.0.2.1",
+	"ip":            "192.0.2.1",
+	"ipv6":          "2001:db8::1",
+	"cidr":          "192.0.2.0/24",
+	"uri":           "https://example.com",
+	"url":           "https://example.com",
+	"iri":           "https://example.com",
+	"uri-reference": "/example",
+	"uri-template":  "https://example.com/{id}",
+	"byte":          "c3RyaW5n",
+}
+
+// words make up seeded plain strings.
+var words = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+// seededEpoch is the start of the year seeded dates and times fall into.
+var seededEpoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// schemaGenerator builds a schema-valid value for responses that declare no example.
+// Schemas currently being expanded are tracked so recursive $refs end instead of
+// being unrolled to an arbitrary depth.
+type schemaGenerator struct {
+	visiting map[*openapi3.Schema]bool
+	// rnd varies the generated values in seeded mode; nil yields the fixed samples.
+	rnd                *rand.Rand
+	arrayMin, arrayMax int
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{visiting: map[*openapi3.Schema]bool{}, arrayMin: 1, arrayMax: 1}
+}
+
+// newGenerator sets up a generator for one response. In seeded mode its values
+// are a function of the seed, the operation and the request's path parameters.
+func newGenerator(cfg GeneratorConfig, method, swaggerPath string, params map[string]string) *schemaGenerator {
+	g := newSchemaGenerator()
+	if cfg.ArrayMin > 0 {
+		g.arrayMin = cfg.ArrayMin
+	}
+	g.arrayMax = max(cfg.ArrayMax, g.arrayMin)
+	if !cfg.Seeded {
+		return g
+	}
+
+	names := make([]string, 0, len(params))
+	for k := range params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s %s", strings.ToUpper(method), swaggerPath)
+	for _, k := range names {
+		_, _ = fmt.Fprintf(h, "\x00%s=%s", k, params[k])
+	}
+	g.rnd = rand.New(rand.NewPCG(uint64(cfg.Seed), h.Sum64())) // #nosec G404 -- reproducible fake data, not secrets
+	return g
+}
+
+// generate returns a value for ref. A missing schema yields an empty object.
+func (g *schemaGenerator) generate(ref *openapi3.SchemaRef) any {
+	if ref == nil || ref.Value == nil {
+		return map[string]any{}
+	}
+	v, ok := g.value(ref)
+	if !ok {
+		return emptyValue(ref.Value)
+	}
+	return v
+}
+
+// value generates ref, reporting false when ref is already being expanded further up.
+func (g *schemaGenerator) value(ref *openapi3.SchemaRef) (any, bool) {
+	if ref == nil || ref.Value == nil {
+		return map[string]any{}, true
+	}
+	s := ref.Value
+	if g.visiting[s] {
+		return nil, false
+	}
+	g.visiting[s] = true
+	defer delete(g.visiting, s)
+
+	switch {
+	case s.Example != nil:
+		return s.Example, true
+	case len(s.Examples) > 0 && s.Examples[0] != nil:
+		return s.Examples[0], true
+	case s.Const != nil:
+		return s.Const, true
+	case s.Default != nil:
+		return s.Default, true
+	case len(s.Enum) > 0:
+		return g.enum(s.Enum), true
+	case len(s.AllOf) > 0:
+		return g.allOf(s), true
+	case len(s.OneOf) > 0:
+		return g.oneOf(s, s.OneOf)
+	case len(s.AnyOf) > 0:
+		return g.oneOf(s, s.AnyOf)
+	}
+
+	switch schemaType(s) {
+	case "array":
+		return g.array(s), true
+	case "object":
+		return g.object(s), true
+	case "string":
+		return g.str(s), true
+	case "integer":
+		return int(g.number(s, true)), true
+	case "number":
+		return g.number(s, false), true
+	case "boolean":
+		return g.rnd == nil || g.rnd.IntN(2) == 0, true
+	case "null":
+		return nil, true
+	}
+	return map[string]any{"ok": true}, true
+}
+
+// enum returns the first non-null value, or a random one in seeded mode.
+func (g *schemaGenerator) enum(values []any) any {
+	var candidates []any
+	for _, v := range values {
+		if v != nil {
+			candidates = append(candidates, v)
+		}
+	}
+	switch {
+	case len(candidates) == 0:
+		return values[0]
+	case g.rnd != nil:
+		return candidates[g.rnd.IntN(len(candidates))]
+	}
+	return candidates[0]
+}
+
+// allOf merges the objects generated for every branch and the schema's own properties.
+func (g *schemaGenerator) allOf(s *openapi3.Schema) any {
+	own := *s
+	own.AllOf = nil
+
+	parts := make([]any, 0, len(s.AllOf)+1)
+	for _, sub := range s.AllOf {
+		if v, ok := g.value(sub); ok {
+			parts = append(parts, v)
+		}
+	}
+	if schemaType(&own) != "" || len(own.OneOf) > 0 || len(own.AnyOf) > 0 {
+		if v, ok := g.value(&openapi3.SchemaRef{Value: &own}); ok {
+			parts = append(parts, v)
+		}
+	}
+
+	merged := map[string]any{}
+	var last any
+	for _, part := range parts {
+		obj, ok := part.(map[string]any)
+		if !ok {
+			last = part
+			continue
+		}
+		for k, v := range obj {
+			merged[k] = v
+		}
+	}
+	if len(merged) == 0 && last != nil {
+		return last
+	}
+	return merged
+}
+
+// oneOf generates the first branch that does not recurse into itself, starting at a
+// random branch in seeded mode. With a discriminator the generated object names the
+// branch it was built from.
+func (g *schemaGenerator) oneOf(s *openapi3.Schema, branches openapi3.SchemaRefs) (any, bool) {
+	start := 0
+	if g.rnd != nil {
+		start = g.rnd.IntN(len(branches))
+	}
+	for i := range branches {
+		branch := branches[(start+i)%len(branches)]
+		if branch == nil || branch.Value == nil || isNullOnly(branch.Value) {
+			continue
+		}
+		v, ok := g.value(branch)
+		if !ok {
+			continue
+		}
+		if obj, isObj := v.(map[string]any); isObj && s.Discriminator != nil && s.Discriminator.PropertyName != "" {
+			if name := discriminatorValue(s.Discriminator, branch.Ref); name != "" {
+				obj[s.Discriminator.PropertyName] = name
+			}
+		}
+		return v, true
+	}
+	for _, branch := range branches {
+		if branch != nil && branch.Value != nil && isNullOnly(branch.Value) {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+func (g *schemaGenerator) array(s *openapi3.Schema) []any {
+	n := uint64(g.arrayMin)
+	if g.rnd != nil && g.arrayMax > g.arrayMin {
+		n += uint64(g.rnd.IntN(g.arrayMax - g.arrayMin + 1))
+	}
+	if s.MinItems > n {
+		n = s.MinItems
+	}
+	if s.MaxItems != nil && *s.MaxItems < n {
+		n = *s.MaxItems
+	}
+
+	if s.Items == nil || s.Items.Value == nil {
+		out := make([]any, s.MinItems)
+		for i := range out {
+			out[i] = map[string]any{}
+		}
+		return out
+	}
+
+	out := make([]any, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v, ok := g.value(s.Items)
+		if !ok {
+			// a recursive item type: stop at the shortest valid array
+			for uint64(len(out)) < s.MinItems {
+				out = append(out, emptyValue(s.Items.Value))
+			}
+			return out
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func (g *schemaGenerator) object(s *openapi3.Schema) map[string]any {
+	out := map[string]any{}
+
+	if s.AdditionalProperties.Schema != nil {
+		if v, ok := g.value(s.AdditionalProperties.Schema); ok {
+			out["key"] = v
+		}
+	} else if s.AdditionalProperties.Has != nil && *s.AdditionalProperties.Has {
+		out["key"] = "value"
+	}
+
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	// sorted, so seeded values do not depend on map order
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := s.Properties[name]
+		if prop == nil || prop.Value == nil || prop.Value.WriteOnly {
+			continue
+		}
+		v, ok := g.value(prop)
+		if !ok {
+			// recursion: optional properties are left out, required ones get the
+			// smallest value their schema allows
+			if !required[name] {
+				continue
+			}
+			v = emptyValue(prop.Value)
+		}
+		out[name] = v
+	}
+	return out
+}
+
+// schemaType is the type a value is generated for: the first non-null declared
+// type, or one inferred from the keywords used.
+func schemaType(s *openapi3.Schema) string {
+	if s.Type != nil {
+		for _, t := range s.Type.Slice() {
+			if t != "null" {
+				return t
+			}
+		}
+		if s.Type.Includes("null") {
+			return "null"
+		}
+	}
+
+	switch {
+	case len(s.Properties) > 0 || s.AdditionalProperties.Schema != nil || s.AdditionalProperties.Has != nil:
+		return "object"
+	case s.Items != nil:
+		return "array"
+	case s.Format != "" || s.Pattern != "" || s.MinLength > 0 || s.MaxLength != nil:
+		return "string"
+	case s.Min != nil || s.Max != nil || s.MultipleOf != nil:
+		return "number"
+	}
+	return ""
+}
+
+func isNullOnly(s *openapi3.Schema) bool {
+	return s.Type != nil && len(s.Type.Slice()) == 1 && s.Type.Is("null")
+}
+
+// emptyValue is the smallest value standing in for a schema that recursion cut short.
+func emptyValue(s *openapi3.Schema) any {
+	if s.Nullable || (s.Type != nil && s.Type.Includes("null")) {
+		return nil
+	}
+	if schemaType(s) == "array" {
+		return []any{}
+	}
+	return map[string]any{}
+}
+
+func discriminatorValue(d *openapi3.Discriminator, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	for name, target := range d.Mapping {
+		if target.Ref == ref {
+			return name
+		}
+	}
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func (g *schemaGenerator) str(s *openapi3.Schema) string {
+	if s.Pattern != "" {
+		if v, ok := g.fromPattern(s.Pattern, s.MinLength, s.MaxLength); ok {
+			return v
+		}
+	}
+	if v, ok := g.format(strings.ToLower(s.Format)); ok {
+		return v
+	}
+
+	v := "string"
+	pad := "x"
+	if g.rnd != nil {
+		v = fmt.Sprintf("%s-%d", words[g.rnd.IntN(len(words))], g.rnd.IntN(1000))
+		pad = string(rune('a' + g.rnd.IntN(26)))
+	}
+	for uint64(len(v)) < s.MinLength {
+		v += pad
+	}
+	if s.MaxLength != nil && uint64(len(v)) > *s.MaxLength {
+		v = v[:*s.MaxLength]
+	}
+	return v
+}
+
+// format returns a value for a known string format, varied in seeded mode.
+func (g *schemaGenerator) format(format string) (string, bool) {
+	v, ok := formatSamples[format]
+	if !ok || g.rnd == nil {
+		return v, ok
+	}
+
+	r := g.rnd
+	at := seededEpoch.Add(time.Duration(r.IntN(365*24*3600)) * time.Second)
+	word := words[r.IntN(len(words))]
+	switch format {
+	case "date-time":
+		return at.Format(time.RFC3339), true
+	case "date":
+		return at.Format(time.DateOnly), true
+	case "time":
+		return at.Format("15:04:05Z"), true
+	case "duration":
+		return fmt.Sprintf("P%dD", 1+r.IntN(30)), true
+	case "uuid":
+		var b [16]byte
+		for i := range b {
+			b[i] = byte(r.IntN(256))
+		}
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), true
+	case "email", "idn-email":
+		return fmt.Sprintf("%s.%d@example.com", word, r.IntN(1000)), true
+	case "hostname", "idn-hostname":
+		return fmt.Sprintf("%s-%d.example.com", word, r.IntN(1000)), true
+	case "ipv4", "ip":
+		return fmt.Sprintf("10.%d.%d.%d", r.IntN(256), r.IntN(256), 1+r.IntN(254)), true
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x:%x", r.IntN(0x10000), 1+r.IntN(0xffff)), true
+	case "cidr":
+		return fmt.Sprintf("10.%d.%d.0/24", r.IntN(256), r.IntN(256)), true
+	case "uri", "url", "iri":
+		return "https://example.com/" + word, true
+	case "uri-reference":
+		return "/" + word, true
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(word)), true
+	}
+	return v, true
+}
+
+// fromPattern builds a string matching pattern, repeating unbounded
+// quantifiers until the length constraints are met.
+func (g *schemaGenerator) fromPattern(pattern string, minLen uint64, maxLen *uint64) (string, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	parsed = parsed.Simplify()
+
+	for extra := 0; extra <= int(minLen)+1; extra++ {
+		var b strings.Builder
+		if !g.writePattern(&b, parsed, extra) {
+			return "", false
+		}
+		v := b.String()
+		n := uint64(len([]rune(v)))
+		if maxLen != nil && n > *maxLen {
+			return "", false
+		}
+		if n >= minLen && re.MatchString(v) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func (g *schemaGenerator) writePattern(b *strings.Builder, re *syntax.Regexp, extra int) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		r, ok := g.pickRune(re.Rune)
+		if !ok {
+			return false
+		}
+		b.WriteRune(r)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('x')
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText,
+		syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+	case syntax.OpCapture:
+		return g.writePattern(b, re.Sub[0], extra)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		n := 0
+		switch re.Op {
+		case syntax.OpPlus:
+			n = 1 + extra
+		case syntax.OpStar:
+			n = extra
+		case syntax.OpRepeat:
+			n = re.Min
+			if re.Max < 0 || re.Max > re.Min {
+				n += extra
+				if re.Max >= 0 && n > re.Max {
+					n = re.Max
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			if !g.writePattern(b, re.Sub[0], extra) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !g.writePattern(b, sub, extra) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return g.writePattern(b, re.Sub[0], extra)
+	default:
+		return false
+	}
+	return true
+}
+
+// pickRune prefers a letter or digit from a character class' ranges. In seeded
+// mode it picks any printable ASCII character of the class.
+func (g *schemaGenerator) pickRune(ranges []rune) (rune, bool) {
+	if len(ranges) < 2 {
+		return 0, false
+	}
+	if g.rnd != nil {
+		var printable []rune
+		for i := 0; i+1 < len(ranges); i += 2 {
+			for r := max(ranges[i], '!'); r <= min(ranges[i+1], '~'); r++ {
+				printable = append(printable, r)
+			}
+		}
+		if len(printable) > 0 {
+			return printable[g.rnd.IntN(len(printable))], true
+		}
+	}
+	for _, want := range []rune{'a', 'A', '0'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= want && want <= ranges[i+1] {
+				return want, true
+			}
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] > ' ' || ranges[i+1] > ' ' {
+			return max(ranges[i], '!'), true
+		}
+	}
+	return ranges[0], true
+}
+
+// number picks 0 when the bounds allow it, otherwise the value closest to the
+// lower (or upper) bound that honors exclusiveness and multipleOf. In seeded mode
+// it picks a random value between the bounds, or from [0, 1000] without them.
+func (g *schemaGenerator) number(s *openapi3.Schema, integer bool) float64 {
+	lo, loExcl, hasLo := lowerBound(s)
+	hi, hiExcl, hasHi := upperBound(s)
+
+	step := 1.0
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		step = *s.MultipleOf
+	}
+	up := func(v float64) float64 {
+		if s.MultipleOf != nil && *s.MultipleOf > 0 {
+			v = math.Ceil(v/step) * step
+		}
+		if integer {
+			v = math.Ceil(v)
+		}
+		return v
+	}
+	down := func(v float64) float64 {
+		if s.MultipleOf != nil && *s.MultipleOf > 0 {
+			v = math.Floor(v/step) * step
+		}
+		if integer {
+			v = math.Floor(v)
+		}
+		return v
+	}
+	inRange := func(v float64) bool {
+		if hasLo && (v < lo || (loExcl && v == lo)) {
+			return false
+		}
+		if hasHi && (v > hi || (hiExcl && v == hi)) {
+			return false
+		}
+		return true
+	}
+
+	candidates := []float64{0}
+	if g.rnd != nil {
+		from, to := 0.0, 1000.0
+		switch {
+		case hasLo && hasHi:
+			from, to = lo, hi
+		case hasLo:
+			from, to = lo, lo+1000
+		case hasHi:
+			from, to = hi-1000, hi
+		}
+		var v float64
+		if integer || (s.MultipleOf != nil && *s.MultipleOf > 0) {
+			first := up(from)
+			steps := int(math.Floor((to - first) / step))
+			v = first
+			if steps > 0 {
+				v += float64(g.rnd.IntN(steps+1)) * step
+			}
+		} else {
+			v = math.Round((from+g.rnd.Float64()*(to-from))*100) / 100
+		}
+		candidates = []float64{v, 0}
+	}
+	if hasLo {
+		candidates = append(candidates, up(lo), up(lo)+step)
+	}
+	if hasHi {
+		candidates = append(candidates, down(hi), down(hi)-step)
+	}
+	if hasLo && hasHi {
+		candidates = append(candidates, up((lo+hi)/2), (lo+hi)/2)
+	}
+	for _, v := range candidates {
+		if inRange(v) {
+			return v
+		}
+	}
+	if hasLo {
+		return lo
+	}
+	return 0
+}
+
+func lowerBound(s *openapi3.Schema) (float64, bool, bool) {
+	if s.ExclusiveMin.Value != nil {
+		return *s.ExclusiveMin.Value, true, true
+	}
+	if s.Min != nil {
+		return *s.Min, s.ExclusiveMin.IsTrue(), true
+	}
+	return 0, false, false
+}
+
+func upperBound(s *openapi3.Schema) (float64, bool, bool) {
+	if s.ExclusiveMax.Value != nil {
+		return *s.ExclusiveMax.Value, true, true
+	}
+	if s.Max != nil {
+		return *s.Max, s.ExclusiveMax.IsTrue(), true
+	}
+	return 0, false, false
+}
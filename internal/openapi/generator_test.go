@@ -0,0 +1,312 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaGenerator_EnumWins(t *testing.T) {
+	v := newSchemaGenerator().generate(&openapi3.SchemaRef{Value: &openapi3.Schema{
+		Enum: []any{"a", "b"},
+	}})
+
+	if v != "a" {
+		t.Fatalf("expected first enum, got %#v", v)
+	}
+}
+
+func TestSchemaGenerator_Primitives(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *openapi3.Schema
+		want any
+	}{
+		{"string", &openapi3.Schema{Type: &openapi3.Types{"string"}}, "string"},
+		{"datetime", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "date-time"}, "2026-01-28T00:00:00Z"},
+		{"integer", &openapi3.Schema{Type: &openapi3.Types{"integer"}}, 0},
+		{"number", &openapi3.Schema{Type: &openapi3.Types{"number"}}, 0.0},
+		{"boolean", &openapi3.Schema{Type: &openapi3.Types{"boolean"}}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newSchemaGenerator().generate(&openapi3.SchemaRef{Value: tc.s})
+			if got != tc.want {
+				t.Fatalf("got %#v want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSchemaGenerator_Array(t *testing.T) {
+	got := newSchemaGenerator().generate(&openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:  &openapi3.Types{"array"},
+		Items: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+	}})
+
+	arr, ok := got.([]any)
+	if !ok || len(arr) != 1 || arr[0] != "string" {
+		t.Fatalf("unexpected: %#v", got)
+	}
+}
+
+func TestSchemaGenerator_ObjectProperties(t *testing.T) {
+	got := newSchemaGenerator().generate(&openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"id":   {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+			"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+		},
+	}})
+
+	m, ok := got.(map[string]any)
+	if !ok || m["id"] != 0 || m["name"] != "string" {
+		t.Fatalf("unexpected: %#v", got)
+	}
+}
+
+func TestSchemaGenerator_AdditionalPropertiesSchema(t *testing.T) {
+	s := &openapi3.Schema{}
+	s.AdditionalProperties.Schema = &openapi3.SchemaRef{
+		Value: &openapi3.Schema{Type: &openapi3.Types{"string"}},
+	}
+
+	got := newSchemaGenerator().generate(&openapi3.SchemaRef{Value: s})
+	m, ok := got.(map[string]any)
+	if !ok || m["key"] != "string" {
+		t.Fatalf("unexpected: %#v", got)
+	}
+}
+
+func TestSchemaGenerator_AdditionalPropertiesTrue(t *testing.T) {
+	s := &openapi3.Schema{
+		Properties: openapi3.Schemas{
+			"a": {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+		},
+	}
+	b := true
+	s.AdditionalProperties.Has = &b
+
+	got := newSchemaGenerator().generate(&openapi3.SchemaRef{Value: s})
+	m, ok := got.(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected: %#v", got)
+	}
+	if m["a"] != 0 {
+		t.Fatalf("expected property a, got %#v", m)
+	}
+	if m["key"] != "value" {
+		t.Fatalf("expected additionalProperties placeholder, got %#v", m)
+	}
+}
+
+func TestSchemaGenerator_NilGuards(t *testing.T) {
+	got := newSchemaGenerator().generate(nil)
+	if _, ok := got.(map[string]any); !ok {
+		t.Fatalf("expected map fallback, got %#v", got)
+	}
+
+	got = newSchemaGenerator().generate(&openapi3.SchemaRef{Value: nil})
+	if _, ok := got.(map[string]any); !ok {
+		t.Fatalf("expected map fallback, got %#v", got)
+	}
+}
+
+func loadComponents(t *testing.T, schemas string) openapi3.Schemas {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{},
+	  "components":{"schemas":` + schemas + `}
+	}`))
+	require.NoError(t, err)
+	return doc.Components.Schemas
+}
+
+// generateValid generates a value for the named schema and checks the schema accepts it.
+func generateValid(t *testing.T, schemas openapi3.Schemas, name string) any {
+	t.Helper()
+	ref := schemas[name]
+	require.NotNil(t, ref, name)
+
+	v := roundTrip(t, newSchemaGenerator().generate(ref))
+	require.NoError(t, ref.Value.VisitJSON(v, openapi3.EnableFormatValidation()), "%s: %#v", name, v)
+	return v
+}
+
+func TestSchemaGenerator_Formats(t *testing.T) {
+	schemas := loadComponents(t, `{
+	  "uuid":{"type":"string","format":"uuid"},
+	  "ipv4":{"type":"string","format":"ipv4"},
+	  "ipv6":{"type":"string","format":"ipv6"},
+	  "email":{"type":"string","format":"email"},
+	  "uri":{"type":"string","format":"uri"},
+	  "date":{"type":"string","format":"date"}
+	}`)
+
+	require.Equal(t, "3fa85f64-5717-4562-b3fc-2c963f66afa6", generateValid(t, schemas, "uuid"))
+	require.Equal(t, "192.0.2.1", generateValid(t, schemas, "ipv4"))
+	require.Equal(t, "2001:db8::1", generateValid(t, schemas, "ipv6"))
+	require.Equal(t, "user@example.com", generateValid(t, schemas, "email"))
+	require.Equal(t, "https://example.com", generateValid(t, schemas, "uri"))
+	require.Equal(t, "2026-01-28", generateValid(t, schemas, "date"))
+}
+
+func TestSchemaGenerator_Constraints(t *testing.T) {
+	schemas := loadComponents(t, `{
+	  "port":{"type":"integer","minimum":1,"maximum":65535},
+	  "negative":{"type":"integer","maximum":0,"exclusiveMaximum":true},
+	  "severity":{"type":"number","minimum":0,"exclusiveMinimum":true,"maximum":10,"multipleOf":0.5},
+	  "even":{"type":"integer","minimum":3,"multipleOf":2},
+	  "long":{"type":"string","minLength":10},
+	  "short":{"type":"string","maxLength":3},
+	  "oid":{"type":"string","pattern":"^1\\.3\\.6\\.1\\.4\\.1\\.25623\\.1\\.[0-9]+$"},
+	  "code":{"type":"string","pattern":"^[A-Z]{3}-\\d{4}$"},
+	  "slug":{"type":"string","pattern":"^[a-z]+$","minLength":5},
+	  "hosts":{"type":"array","minItems":3,"items":{"type":"string","format":"ipv4"}},
+	  "none":{"type":"array","maxItems":0,"items":{"type":"string"}},
+	  "defaulted":{"type":"string","enum":["queued","running"],"default":"running"},
+	  "exampled":{"type":"object","example":{"id":"fixed"},"properties":{"id":{"type":"string"}}},
+	  "nullable":{"type":"string","nullable":true,"format":"date-time"}
+	}`)
+
+	require.Equal(t, float64(1), generateValid(t, schemas, "port"))
+	require.Equal(t, float64(-1), generateValid(t, schemas, "negative"))
+	require.Equal(t, 0.5, generateValid(t, schemas, "severity"))
+	require.Equal(t, float64(4), generateValid(t, schemas, "even"))
+	require.Len(t, generateValid(t, schemas, "long"), 10)
+	require.Equal(t, "str", generateValid(t, schemas, "short"))
+	require.Equal(t, "1.3.6.1.4.1.25623.1.0", generateValid(t, schemas, "oid"))
+	require.Equal(t, "AAA-0000", generateValid(t, schemas, "code"))
+	require.Equal(t, "aaaaa", generateValid(t, schemas, "slug"))
+	require.Equal(t, []any{"192.0.2.1", "192.0.2.1", "192.0.2.1"}, generateValid(t, schemas, "hosts"))
+	require.Equal(t, []any{}, generateValid(t, schemas, "none"))
+	require.Equal(t, "running", generateValid(t, schemas, "defaulted"))
+	require.Equal(t, map[string]any{"id": "fixed"}, generateValid(t, schemas, "exampled"))
+	require.Equal(t, "2026-01-28T00:00:00Z", generateValid(t, schemas, "nullable"))
+}
+
+func TestSchemaGenerator_Composition(t *testing.T) {
+	schemas := loadComponents(t, `{
+	  "Base":{"type":"object","required":["id"],"properties":{"id":{"type":"string","format":"uuid"}}},
+	  "Scan":{"allOf":[
+		{"$ref":"#/components/schemas/Base"},
+		{"type":"object","properties":{"status":{"type":"string","enum":["requested","running"]}}}
+	  ]},
+	  "Host":{"type":"object","properties":{"kind":{"type":"string"},"ip":{"type":"string","format":"ipv4"}}},
+	  "Range":{"type":"object","properties":{"kind":{"type":"string"},"cidr":{"type":"string","format":"cidr"}}},
+	  "Target":{
+		"oneOf":[{"$ref":"#/components/schemas/Host"},{"$ref":"#/components/schemas/Range"}],
+		"discriminator":{"propertyName":"kind","mapping":{"host":"#/components/schemas/Host"}}
+	  },
+	  "MaybeCount":{"anyOf":[{"type":"integer","minimum":7}]}
+	}`)
+
+	require.Equal(t, map[string]any{
+		"id":     "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+		"status": "requested",
+	}, generateValid(t, schemas, "Scan"))
+	require.Equal(t, map[string]any{"kind": "host", "ip": "192.0.2.1"}, generateValid(t, schemas, "Target"))
+	require.Equal(t, float64(7), generateValid(t, schemas, "MaybeCount"))
+}
+
+func TestSchemaGenerator_RecursiveRefs(t *testing.T) {
+	schemas := loadComponents(t, `{
+	  "Node":{
+		"type":"object",
+		"required":["name","children"],
+		"properties":{
+		  "name":{"type":"string"},
+		  "parent":{"$ref":"#/components/schemas/Node"},
+		  "children":{"type":"array","items":{"$ref":"#/components/schemas/Node"}}
+		}
+	  },
+	  "Pair":{
+		"type":"object",
+		"required":["left"],
+		"properties":{"left":{"$ref":"#/components/schemas/Pair"}}
+	  }
+	}`)
+
+	require.Equal(t, map[string]any{"name": "string", "children": []any{}}, generateValid(t, schemas, "Node"))
+
+	// a required self reference cannot be satisfied; the recursion still ends
+	require.Equal(t, map[string]any{"left": map[string]any{}}, newSchemaGenerator().generate(schemas["Pair"]))
+}
+
+func TestSchemaGenerator_WriteOnlySkipped(t *testing.T) {
+	schemas := loadComponents(t, `{
+	  "User":{"type":"object","properties":{"name":{"type":"string"},"password":{"type":"string","writeOnly":true}}}
+	}`)
+
+	require.Equal(t, map[string]any{"name": "string"}, newSchemaGenerator().generate(schemas["User"]))
+}
+
+func TestSchemaGenerator_Seeded(t *testing.T) {
+	schemas := loadComponents(t, `{
+	  "Scan":{
+		"type":"object",
+		"required":["id","target","hosts","progress"],
+		"properties":{
+		  "id":{"type":"string","format":"uuid"},
+		  "target":{"type":"string","format":"ipv4"},
+		  "hosts":{"type":"array","maxItems":4,"items":{"type":"string","format":"hostname"}},
+		  "progress":{"type":"integer","minimum":0,"maximum":100},
+		  "status":{"type":"string","enum":["requested","running","succeeded"]},
+		  "created":{"type":"string","format":"date-time"},
+		  "note":{"type":"string","minLength":3,"maxLength":12},
+		  "code":{"type":"string","pattern":"^[A-Z]{3}-\\d{4}$"}
+		}
+	  }
+	}`)
+	cfg := GeneratorConfig{Seeded: true, Seed: 42, ArrayMin: 2, ArrayMax: 6}
+
+	gen := func(cfg GeneratorConfig, id string) map[string]any {
+		v := newGenerator(cfg, "GET", "/scans/{id}", map[string]string{"id": id}).generate(schemas["Scan"])
+		require.NoError(t, schemas["Scan"].Value.VisitJSON(roundTrip(t, v), openapi3.EnableFormatValidation()))
+		return v.(map[string]any)
+	}
+
+	a, b := gen(cfg, "a"), gen(cfg, "b")
+	require.Equal(t, a, gen(cfg, "a"), "same seed, route and params give the same document")
+	require.NotEqual(t, a["id"], b["id"])
+
+	other := cfg
+	other.Seed = 43
+	require.NotEqual(t, a["id"], gen(other, "a")["id"])
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		hosts := gen(cfg, id)["hosts"].([]any)
+		require.GreaterOrEqual(t, len(hosts), 2)
+		require.LessOrEqual(t, len(hosts), 4, "maxItems caps the configured length")
+	}
+}
+
+func TestSchemaGenerator_StaticArrayLength(t *testing.T) {
+	schemas := loadComponents(t, `{
+	  "List":{"type":"array","items":{"type":"integer"}},
+	  "Short":{"type":"array","maxItems":2,"items":{"type":"integer"}}
+	}`)
+	cfg := GeneratorConfig{ArrayMin: 3, ArrayMax: 10}
+
+	require.Equal(t, []any{0, 0, 0}, newGenerator(cfg, "GET", "/x", nil).generate(schemas["List"]))
+	require.Equal(t, []any{0, 0}, newGenerator(cfg, "GET", "/x", nil).generate(schemas["Short"]))
+	require.Len(t, newGenerator(GeneratorConfig{}, "GET", "/x", nil).generate(schemas["List"]), 1)
+}
+
+func roundTrip(t *testing.T, v any) any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	var out any
+	require.NoError(t, json.Unmarshal(b, &out))
+	return out
+}
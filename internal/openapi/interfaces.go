@@ -12,11 +12,18 @@ import (
 
 type IRouterProvider interface {
 	FindRoute(method, path string) *Route
+	AllowedMethods(path string) []string
 	GetRoutes() []Route
+	CollectionOf(swaggerTpl string) (collection, idParam string, ok bool)
+	ItemOf(swaggerTpl string) (item string, ok bool)
+	StripBasePath(path string) (rel string, ok bool)
+	BasePaths() []string
 }
 
 type ISpecProvider interface {
-	TryGetExampleBody(swaggerPath, method string) ([]byte, bool)
+	TryGetExampleResponse(swaggerPath, method string, params map[string]string, pref Preference) (*ExampleResponse, bool)
+	HasExample(swaggerPath, method string) bool
+	TryGetStatusExampleBody(swaggerPath, method string, status int, params map[string]string) ([]byte, bool)
 	FindOperation(swaggerPath, method string) *openapi3.Operation
 	GetSpec() *Spec
 }
@@ -24,4 +31,6 @@ type ISpecProvider interface {
 type IValidator interface {
 	HasRequiredBodyParam(swaggerPath, method string) bool
 	IsEmptyBody(r *http.Request) (bool, error)
+	ValidateRequest(r *http.Request, route *Route) []ValidationIssue
+	ValidateResponse(route *Route, status int, headers map[string]string, body []byte) []ValidationIssue
 }
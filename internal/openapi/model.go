@@ -16,6 +16,65 @@ type Route struct {
 	Swagger    string
 	Regex      *regexp.Regexp
 	SampleFile string
+	// Params holds the path parameter values of a matched request, keyed by name.
+	Params map[string]string
+	// Loose marks a match whose path parameters fail their schemas, found only
+	// because no route accepts them; strict validation answers it with 400.
+	Loose bool
+}
+
+// ValidationIssue is a single request violation reported in strict validation mode.
+// Pointer is a JSON pointer into the request, e.g. "/body/target/hosts/0" or "/query/limit".
+type ValidationIssue struct {
+	In      string `json:"in"`
+	Name    string `json:"name,omitempty"`
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// BaseConfig sets how request paths relate to the spec's path keys.
+type BaseConfig struct {
+	// Paths replace the base paths declared in the spec when set.
+	Paths []string
+	// Require rejects requests that do not start with one of the base paths.
+	Require bool
+}
+
+// Preference is what a request asks of its spec response, e.g. through
+// "Prefer: code=404, example=notFound" or "Prefer: dynamic=true".
+type Preference struct {
+	// Code selects the response declared for that status; 0 picks the best success response.
+	Code int `json:"code,omitempty"`
+	// Example selects a named example of the response.
+	Example string `json:"example,omitempty"`
+	// Dynamic generates the body from the schema even when examples exist.
+	Dynamic bool `json:"dynamic,omitempty"`
+}
+
+// IsZero reports whether the request states no preference.
+func (p Preference) IsZero() bool {
+	return p == Preference{}
+}
+
+// ExampleResponse is a response answered from the spec.
+type ExampleResponse struct {
+	Status  int
+	Headers map[string]string
+	// ContentType is empty for responses without content.
+	ContentType string
+	Body        []byte
+}
+
+// GeneratorConfig sets how response bodies are generated from schemas.
+type GeneratorConfig struct {
+	// Seeded derives generated values from Seed, the operation and the request's
+	// path parameters instead of using one fixed value per schema.
+	Seeded bool
+	Seed   int64
+	// ArrayMin and ArrayMax bound the length of generated arrays (at least 1);
+	// without Seeded arrays get ArrayMin items. minItems and maxItems still apply.
+	ArrayMin int
+	ArrayMax int
 }
 
 type Spec struct {
@@ -23,7 +82,19 @@ type Spec struct {
 	Doc2 *openapi2.T
 }
 
+// versionProbe reads the version fields; YAML specs may carry them as numbers.
 type versionProbe struct {
-	Swagger string `json:"swagger"`
-	OpenAPI string `json:"openapi"`
+	Swagger any `json:"swagger"`
+	OpenAPI any `json:"openapi"`
+}
+
+// isSwagger2 reports whether the swagger field is 2.0, written as "2.0", 2.0 or 2.
+func (v versionProbe) isSwagger2() bool {
+	switch s := v.Swagger.(type) {
+	case string:
+		return s == "2.0" || s == "2"
+	case float64:
+		return s == 2
+	}
+	return false
 }
@@ -7,79 +7,170 @@ package openapi
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
 type RouterProvider struct {
 	routes []Route
+	root   *routeNode
+
+	basePaths   []string
+	requireBase bool
 }
 
+// NewRouterProvider routes by the spec's paths, accepting them with or without
+// the spec's base paths.
 func NewRouterProvider(spec *Spec) IRouterProvider {
+	return NewRouterProviderWithBase(spec, BaseConfig{})
+}
+
+func NewRouterProviderWithBase(spec *Spec, base BaseConfig) IRouterProvider {
 	if spec == nil || spec.Doc3 == nil || spec.Doc3.Paths == nil {
 		return nil
 	}
 
-	var out []Route
-	for swaggerPath, item := range spec.Doc3.Paths.Map() {
+	p := &RouterProvider{root: newRouteNode(), requireBase: base.Require}
+	// sorted, so templates that only differ in parameter names resolve the same way every time
+	for _, swaggerPath := range spec.Doc3.Paths.InMatchingOrder() {
+		item := spec.Doc3.Paths.Value(swaggerPath)
 		if item == nil {
 			continue
 		}
 
+		checks := pathParamChecks(item)
+		methods := make([]string, 0, len(item.Operations()))
 		for method := range item.Operations() {
-			m := strings.ToUpper(method)
-			out = append(out, Route{
+			methods = append(methods, strings.ToUpper(method))
+		}
+		sort.Strings(methods)
+
+		for _, m := range methods {
+			p.routes = append(p.routes, Route{
 				Method:     m,
 				Swagger:    swaggerPath,
 				Regex:      swaggerPathToRegex(swaggerPath),
 				SampleFile: swaggerPathToSampleName(m, swaggerPath),
 			})
+			p.root.insert(splitPath(swaggerPath), checks, m, len(p.routes)-1)
 		}
 	}
-	return &RouterProvider{routes: out}
+
+	p.basePaths = NormalizeBasePaths(base.Paths)
+	if len(base.Paths) == 0 {
+		p.basePaths = BasePaths(spec)
+	}
+	return p
 }
 
+// FindRoute matches a path relative to the spec's path keys; see StripBasePath.
+// Literal segments win over parameters, and parameter values must satisfy the
+// parameter's schema. When only values failing their schemas match, the route
+// is returned marked Loose. The returned route carries the extracted Params.
 func (p *RouterProvider) FindRoute(method, path string) *Route {
-	method = strings.ToUpper(method)
+	segments, method := splitPath(path), strings.ToUpper(method)
+	params := map[string]string{}
+	loose := false
+	i := p.root.match(segments, method, params, false)
+	if i < 0 {
+		params = map[string]string{}
+		i, loose = p.root.match(segments, method, params, true), true
+	}
+	if i < 0 {
+		return nil
+	}
 
-	var best *Route
-	bestScore := -1
+	r := p.routes[i]
+	r.Params = params
+	r.Loose = loose
+	return &r
+}
 
-	for i := range p.routes {
-		r := &p.routes[i]
-		if r.Method != method {
-			continue
-		}
-		if !r.Regex.MatchString(path) {
-			continue
-		}
+// AllowedMethods lists the methods the spec declares for a path, sorted.
+// It is empty when no template matches the path.
+func (p *RouterProvider) AllowedMethods(path string) []string {
+	set := map[string]bool{}
+	p.root.methods(splitPath(path), set)
 
-		score := p.routeSpecificityScore(r.Swagger)
-		if score > bestScore {
-			best = r
-			bestScore = score
-		}
+	out := make([]string, 0, len(set))
+	for m := range set {
+		out = append(out, m)
 	}
-
-	return best
+	sort.Strings(out)
+	return out
 }
 
 func (p *RouterProvider) GetRoutes() []Route {
 	return p.routes
 }
 
-func (p *RouterProvider) routeSpecificityScore(swaggerPath string) int {
-	parts := strings.Split(strings.Trim(swaggerPath, "/"), "/")
-	score := 0
-	for _, p := range parts {
-		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
-			score += 0 // param segment
-		} else {
-			score += 10
+// CollectionOf maps an item template such as /scans/{id} to its collection
+// template /scans and the name of the id parameter, when the spec has both paths.
+func (p *RouterProvider) CollectionOf(swaggerTpl string) (string, string, bool) {
+	i := strings.LastIndex(swaggerTpl, "/")
+	if i <= 0 {
+		return "", "", false
+	}
+	collection, last := swaggerTpl[:i], swaggerTpl[i+1:]
+	if !strings.HasPrefix(last, "{") || !strings.HasSuffix(last, "}") {
+		return "", "", false
+	}
+	if !p.hasPath(collection) {
+		return "", "", false
+	}
+	return collection, strings.TrimSuffix(strings.TrimPrefix(last, "{"), "}"), true
+}
+
+// ItemOf maps a collection template such as /scans to its item template
+// /scans/{id}, when the spec has both paths.
+func (p *RouterProvider) ItemOf(swaggerTpl string) (string, bool) {
+	prefix := strings.TrimSuffix(swaggerTpl, "/") + "/"
+	for _, r := range p.routes {
+		last, ok := strings.CutPrefix(r.Swagger, prefix)
+		if ok && !strings.Contains(last, "/") && strings.HasPrefix(last, "{") && strings.HasSuffix(last, "}") {
+			return r.Swagger, true
 		}
 	}
+	return "", false
+}
+
+func (p *RouterProvider) hasPath(swaggerTpl string) bool {
+	for _, r := range p.routes {
+		if r.Swagger == swaggerTpl {
+			return true
+		}
+	}
+	return false
+}
 
-	score += len(parts)
-	return score
+// PathParams extracts the path parameter values of an actual request path
+// using the route's swagger template, keyed by parameter name. Routes returned
+// by FindRoute already carry them in Params.
+func (r *Route) PathParams(path string) map[string]string {
+	out := map[string]string{}
+	if r == nil || r.Regex == nil {
+		return out
+	}
+	if r.Params != nil {
+		return r.Params
+	}
+
+	m := r.Regex.FindStringSubmatch(path)
+	if m == nil {
+		return out
+	}
+
+	i := 1
+	for _, p := range strings.Split(r.Swagger, "/") {
+		if !strings.HasPrefix(p, "{") || !strings.HasSuffix(p, "}") {
+			continue
+		}
+		if i < len(m) {
+			out[strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}")] = m[i]
+		}
+		i++
+	}
+	return out
 }
 
 func swaggerPathToSampleName(method, swaggerPath string) string {
@@ -5,6 +5,7 @@
 package openapi
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -48,27 +49,18 @@ func TestSwaggerPathToSampleName(t *testing.T) {
 }
 
 func TestRouterProvider_FindRoute(t *testing.T) {
-	p := &RouterProvider{
-		routes: []Route{
-			{
-				Method:     "GET",
-				Swagger:    "/users/{id}",
-				Regex:      swaggerPathToRegex("/users/{id}"),
-				SampleFile: "GET__users_{id}.json",
-			},
-			{
-				Method:     "POST",
-				Swagger:    "/users",
-				Regex:      swaggerPathToRegex("/users"),
-				SampleFile: "POST__users.json",
-			},
-		},
-	}
+	paths := openapi3.NewPaths()
+	paths.Set("/users/{id}", &openapi3.PathItem{Get: &openapi3.Operation{Responses: openapi3.NewResponses()}})
+	paths.Set("/users", &openapi3.PathItem{Post: &openapi3.Operation{Responses: openapi3.NewResponses()}})
+	p := NewRouterProvider(&Spec{Doc3: &openapi3.T{Paths: paths}})
 
 	r := p.FindRoute("get", "/users/55")
 	if r == nil || r.Swagger != "/users/{id}" {
 		t.Fatalf("expected to find /users/{id}, got %#v", r)
 	}
+	if r.Params["id"] != "55" {
+		t.Fatalf("expected extracted id, got %v", r.Params)
+	}
 
 	if p.FindRoute("GET", "/nope") != nil {
 		t.Fatalf("expected nil")
@@ -154,3 +146,115 @@ func TestRouterProvider_GetRoutes_ReturnsRoutes(t *testing.T) {
 		t.Fatalf("unexpected routes: %#v", got)
 	}
 }
+
+func TestRouterProvider_CollectionOfAndItemOf(t *testing.T) {
+	var routes []Route
+	for _, tpl := range []string{"/scans", "/scans/{id}", "/scans/{id}/results", "/scans/{id}/results/{rid}", "/vts/{oid}", "/health"} {
+		routes = append(routes, Route{Method: "GET", Swagger: tpl, Regex: swaggerPathToRegex(tpl)})
+	}
+	p := &RouterProvider{routes: routes}
+
+	cases := []struct {
+		item, collection, param string
+		ok                      bool
+	}{
+		{"/scans/{id}", "/scans", "id", true},
+		{"/scans/{id}/results/{rid}", "/scans/{id}/results", "rid", true},
+		{"/vts/{oid}", "", "", false},          // no /vts path
+		{"/scans/{id}/results", "", "", false}, // static last segment
+		{"/health", "", "", false},
+	}
+	for _, tc := range cases {
+		c, param, ok := p.CollectionOf(tc.item)
+		if c != tc.collection || param != tc.param || ok != tc.ok {
+			t.Fatalf("CollectionOf(%s) = %q, %q, %v", tc.item, c, param, ok)
+		}
+	}
+
+	if item, ok := p.ItemOf("/scans"); !ok || item != "/scans/{id}" {
+		t.Fatalf("ItemOf(/scans) = %q, %v", item, ok)
+	}
+	if item, ok := p.ItemOf("/scans/{id}/results"); !ok || item != "/scans/{id}/results/{rid}" {
+		t.Fatalf("ItemOf(/scans/{id}/results) = %q, %v", item, ok)
+	}
+	if _, ok := p.ItemOf("/health"); ok {
+		t.Fatalf("expected /health to have no item template")
+	}
+}
+
+func TestRouterProvider_FindRoute_PrefersLiteralsAndChecksParamSchemas(t *testing.T) {
+	op := func(params ...*openapi3.ParameterRef) *openapi3.Operation {
+		return &openapi3.Operation{Parameters: params, Responses: openapi3.NewResponses()}
+	}
+	pathParam := func(name string, schema *openapi3.Schema) *openapi3.ParameterRef {
+		return &openapi3.ParameterRef{Value: &openapi3.Parameter{Name: name, In: "path", Required: true, Schema: schema.NewRef()}}
+	}
+
+	paths := openapi3.NewPaths()
+	paths.Set("/scans/{id}", &openapi3.PathItem{
+		Get:    op(pathParam("id", openapi3.NewUUIDSchema())),
+		Delete: op(pathParam("id", openapi3.NewUUIDSchema())),
+	})
+	paths.Set("/scans/preferences", &openapi3.PathItem{Get: op()})
+	paths.Set("/scans/{id}/results/{rid}", &openapi3.PathItem{
+		Get: op(pathParam("id", openapi3.NewUUIDSchema()), pathParam("rid", openapi3.NewIntegerSchema())),
+	})
+	paths.Set("/vts/{oid}", &openapi3.PathItem{Get: op(pathParam("oid", openapi3.NewStringSchema().WithPattern(`^[0-9.]+$`)))})
+	paths.Set("/vts/{family}", &openapi3.PathItem{Get: op(pathParam("family", openapi3.NewStringSchema().WithEnum("windows", "linux")))})
+	paths.Set("/vts/{name}", &openapi3.PathItem{Get: op()})
+	p := NewRouterProvider(&Spec{Doc3: &openapi3.T{Paths: paths}})
+
+	const id = "6c0e0a8e-9d7b-4c5e-8f43-2a4f0b1e6a11"
+	cases := []struct {
+		method, path, want string
+	}{
+		{"GET", "/scans/preferences", "/scans/preferences"},
+		{"GET", "/scans/" + id, "/scans/{id}"},
+		{"GET", "/scans/" + id + "/", "/scans/{id}"},
+		{"DELETE", "/scans/" + id, "/scans/{id}"},
+		{"GET", "/scans/not-a-uuid", ""},
+		{"DELETE", "/scans/preferences", ""},
+		{"GET", "/scans/" + id + "/results/7", "/scans/{id}/results/{rid}"},
+		{"GET", "/scans/" + id + "/results/seven", ""},
+		{"GET", "/vts/1.3.6.1", "/vts/{oid}"},
+		{"GET", "/vts/linux", "/vts/{family}"},
+		{"GET", "/vts/anything", "/vts/{name}"},
+		{"GET", "/scans//results", ""},
+	}
+	for _, tc := range cases {
+		r := p.FindRoute(tc.method, tc.path)
+		got := ""
+		if r != nil && !r.Loose {
+			got = r.Swagger
+		}
+		if got != tc.want {
+			t.Fatalf("%s %s: expected %q, got %q", tc.method, tc.path, tc.want, got)
+		}
+	}
+
+	// values failing their schemas still find the route, marked loose, for strict validation
+	if r := p.FindRoute("GET", "/scans/not-a-uuid"); r == nil || !r.Loose || r.Swagger != "/scans/{id}" || r.Params["id"] != "not-a-uuid" {
+		t.Fatalf("expected a loose /scans/{id} match, got %+v", r)
+	}
+	if r := p.FindRoute("GET", "/scans/"+id); r == nil || r.Loose {
+		t.Fatalf("expected a typed match, got %+v", r)
+	}
+
+	r := p.FindRoute("GET", "/scans/"+id+"/results/7")
+	if r.Params["id"] != id || r.Params["rid"] != "7" || len(r.Params) != 2 {
+		t.Fatalf("unexpected params: %v", r.Params)
+	}
+	if got := r.PathParams("/ignored"); got["rid"] != "7" {
+		t.Fatalf("expected PathParams to reuse the matched params, got %v", got)
+	}
+
+	if got := p.AllowedMethods("/scans/" + id); strings.Join(got, ",") != "DELETE,GET" {
+		t.Fatalf("unexpected allowed methods: %v", got)
+	}
+	if got := p.AllowedMethods("/scans/preferences"); strings.Join(got, ",") != "GET" {
+		t.Fatalf("unexpected allowed methods: %v", got)
+	}
+	if got := p.AllowedMethods("/nope"); len(got) != 0 {
+		t.Fatalf("expected no methods, got %v", got)
+	}
+}
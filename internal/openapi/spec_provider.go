@@ -5,6 +5,7 @@
 package openapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -20,22 +21,35 @@ import (
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
 )
 
 type SpecProvider struct {
 	path string
 	spec *Spec
+	gen  GeneratorConfig
 	log  *logrus.Logger
 }
 
 func NewSpecProvider(path string, log *logrus.Logger) (ISpecProvider, error) {
+	return NewSpecProviderWithGenerator(path, GeneratorConfig{}, log)
+}
+
+// NewSpecProviderWithGenerator loads the spec like NewSpecProvider; gen sets up
+// the bodies generated for responses without an example.
+func NewSpecProviderWithGenerator(path string, gen GeneratorConfig, log *logrus.Logger) (ISpecProvider, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read spec: %w", err)
 	}
+	if b, err = specJSON(path, b); err != nil {
+		return nil, err
+	}
 
 	var probe versionProbe
-	_ = json.Unmarshal(b, &probe)
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, fmt.Errorf("parse spec json: %w", err)
+	}
 
 	abs, _ := filepath.Abs(path)
 	loc := &url.URL{Scheme: "file", Path: abs}
@@ -44,7 +58,10 @@ func NewSpecProvider(path string, log *logrus.Logger) (ISpecProvider, error) {
 	loader.IsExternalRefsAllowed = true
 
 	// Swagger 2.0
-	if probe.Swagger == "2.0" {
+	if probe.isSwagger2() {
+		if b, err = withSwaggerVersionString(b); err != nil {
+			return nil, err
+		}
 		var doc2 openapi2.T
 		if err := json.Unmarshal(b, &doc2); err != nil {
 			return nil, fmt.Errorf("parse swagger2 json: %w", err)
@@ -68,6 +85,7 @@ func NewSpecProvider(path string, log *logrus.Logger) (ISpecProvider, error) {
 		return &SpecProvider{
 			path: path,
 			spec: &Spec{Doc2: &doc2, Doc3: doc3},
+			gen:  gen,
 			log:  log,
 		}, nil
 	}
@@ -87,36 +105,139 @@ func NewSpecProvider(path string, log *logrus.Logger) (ISpecProvider, error) {
 	return &SpecProvider{
 		path: path,
 		spec: &Spec{Doc3: &doc3},
+		gen:  gen,
 		log:  log,
 	}, nil
 }
 
+// withSwaggerVersionString rewrites a numeric swagger version to "2.0", the
+// string openapi2.T expects.
+func withSwaggerVersionString(b []byte) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("parse swagger2 json: %w", err)
+	}
+	if bytes.HasPrefix(doc["swagger"], []byte(`"`)) {
+		return b, nil
+	}
+	doc["swagger"] = json.RawMessage(`"2.0"`)
+	return json.Marshal(doc)
+}
+
+// specJSON returns the document as JSON. YAML is detected by a .yaml/.yml extension
+// or, for any other name, by content that does not start like a JSON object.
+func specJSON(path string, b []byte) ([]byte, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")))
+	if ext != ".yaml" && ext != ".yml" && bytes.HasPrefix(trimmed, []byte("{")) {
+		return b, nil
+	}
+
+	j, err := yaml.YAMLToJSON(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec yaml: %w", err)
+	}
+	return j, nil
+}
+
 func (sp *SpecProvider) GetSpec() *Spec {
 	return sp.spec
 }
 
-func (p *SpecProvider) TryGetExampleBody(swaggerPath, method string) ([]byte, bool) {
+// TryGetExampleResponse returns the operation's preferred response: its status,
+// headers, media type and example, or a body generated from its schema. params are
+// the request's path parameters; they seed the generated values in seeded mode.
+// pref selects another status code, a named example or a generated body; it fails
+// when the spec cannot honor it.
+func (p *SpecProvider) TryGetExampleResponse(swaggerPath, method string, params map[string]string, pref Preference) (*ExampleResponse, bool) {
 	op := p.FindOperation(swaggerPath, method)
 	if op == nil || op.Responses == nil {
 		return nil, false
 	}
 
-	respRef := p.pickBestResponseRef(op.Responses)
+	code, respRef := p.pickResponseRef(op.Responses, pref.Code)
+	if respRef == nil && pref.Code != 0 {
+		return nil, false
+	}
 	if respRef == nil || respRef.Value == nil {
-		b, _ := json.Marshal(map[string]any{"ok": true})
-		return b, true
+		if pref.Example != "" {
+			return nil, false
+		}
+		// nothing declared to follow: the status alone
+		return &ExampleResponse{Status: responseStatus(code, pref.Code), Headers: map[string]string{}}, true
 	}
 
-	if b, ok := p.extractExampleFromResponse(respRef.Value); ok {
-		return b, true
+	g := newGenerator(p.gen, method, swaggerPath, params)
+	out := &ExampleResponse{Status: responseStatus(code, pref.Code)}
+	if len(respRef.Value.Content) == 0 || !statusAllowsBody(out.Status) {
+		if pref.Example != "" {
+			return nil, false
+		}
+		out.Headers = responseHeaders(respRef.Value, g)
+		return out, true
 	}
 
-	if b, ok := p.generateFromResponseSchema(respRef.Value); ok {
+	var ok bool
+	if !pref.Dynamic {
+		out.ContentType, out.Body, ok = p.extractExampleFromResponse(respRef.Value, pref.Example)
+		if !ok && pref.Example != "" {
+			return nil, false
+		}
+	}
+	if !ok {
+		out.ContentType, out.Body, ok = p.generateFromResponseSchema(respRef.Value, g)
+	}
+	if !ok && pref.Dynamic {
+		out.ContentType, out.Body, ok = p.extractExampleFromResponse(respRef.Value, "")
+	}
+	if !ok && !hasJSONMediaType(respRef.Value.Content) {
+		// e.g. a PDF without example: the declared media type, but no body
+		out.ContentType = servedContentType(mediaTypes(respRef.Value.Content)[0])
+	}
+	out.Headers = responseHeaders(respRef.Value, g)
+	return out, true
+}
+
+// TryGetStatusExampleBody returns the example (or a schema-generated body) of the
+// response declared for that status code or its range (e.g. 4XX). It does not fall
+// back to other responses, so callers can tell a spec-defined error shape from none.
+func (p *SpecProvider) TryGetStatusExampleBody(swaggerPath, method string, status int, params map[string]string) ([]byte, bool) {
+	op := p.FindOperation(swaggerPath, method)
+	if op == nil || op.Responses == nil {
+		return nil, false
+	}
+
+	respRef := op.Responses.Status(status)
+	if respRef == nil || respRef.Value == nil {
+		return nil, false
+	}
+
+	if _, b, ok := p.extractExampleFromResponse(respRef.Value, ""); ok {
 		return b, true
 	}
+	_, b, ok := p.generateFromResponseSchema(respRef.Value, newGenerator(p.gen, method, swaggerPath, params))
+	return b, ok
+}
+
+// HasExample reports whether the spec alone can answer the operation: the response
+// TryGetExampleResponse would pick either declares an explicit example or has no
+// content at all. Schema-generated bodies do not count.
+func (p *SpecProvider) HasExample(swaggerPath, method string) bool {
+	op := p.FindOperation(swaggerPath, method)
+	if op == nil || op.Responses == nil {
+		return false
+	}
+
+	_, respRef := p.pickBestResponseRef(op.Responses)
+	if respRef == nil || respRef.Value == nil {
+		return false
+	}
+	if len(respRef.Value.Content) == 0 {
+		return true
+	}
 
-	b, _ := json.Marshal(map[string]any{"ok": true})
-	return b, true
+	_, _, ok := p.extractExampleFromResponse(respRef.Value, "")
+	return ok
 }
 
 func (p *SpecProvider) FindOperation(swaggerPath, method string) *openapi3.Operation {
@@ -130,15 +251,33 @@ func (p *SpecProvider) FindOperation(swaggerPath, method string) *openapi3.Opera
 	return item.GetOperation(strings.ToUpper(method))
 }
 
-func (p *SpecProvider) pickBestResponseRef(resps *openapi3.Responses) *openapi3.ResponseRef {
+// pickResponseRef returns the response declared for code, its range (e.g. 4XX) or
+// the default response, with the key it is declared under. Without a code it picks
+// the best success response.
+func (p *SpecProvider) pickResponseRef(resps *openapi3.Responses, code int) (string, *openapi3.ResponseRef) {
+	if code == 0 {
+		return p.pickBestResponseRef(resps)
+	}
 	if resps == nil {
-		return nil
+		return "", nil
+	}
+	for _, key := range []string{strconv.Itoa(code), fmt.Sprintf("%dXX", code/100), "default"} {
+		if r := resps.Value(key); r != nil {
+			return key, r
+		}
+	}
+	return "", nil
+}
+
+func (p *SpecProvider) pickBestResponseRef(resps *openapi3.Responses) (string, *openapi3.ResponseRef) {
+	if resps == nil {
+		return "", nil
 	}
 
 	// Prefer 200/201/202/204 if present
 	for _, code := range []string{"200", "201", "202", "204"} {
 		if r := resps.Value(code); r != nil {
-			return r
+			return code, r
 		}
 	}
 
@@ -152,142 +291,232 @@ func (p *SpecProvider) pickBestResponseRef(resps *openapi3.Responses) *openapi3.
 	sort.Ints(twos)
 	for _, n := range twos {
 		if r := resps.Value(strconv.Itoa(n)); r != nil {
-			return r
+			return strconv.Itoa(n), r
 		}
 	}
 
 	// Then default
 	if r := resps.Value("default"); r != nil {
-		return r
+		return "default", r
 	}
 
-	// Otherwise: return any
-	for _, r := range resps.Map() {
-		if r != nil {
-			return r
+	// Otherwise: the first declared code
+	codes := make([]string, 0, resps.Len())
+	for k := range resps.Map() {
+		codes = append(codes, k)
+	}
+	sort.Strings(codes)
+	for _, k := range codes {
+		if r := resps.Value(k); r != nil {
+			return k, r
 		}
 	}
-	return nil
+	return "", nil
 }
 
-func (p *SpecProvider) extractExampleFromResponse(resp *openapi3.Response) ([]byte, bool) {
-	if resp == nil || resp.Content == nil {
-		return nil, false
+// responseStatus is the status code to answer with for a response declared under key.
+func responseStatus(key string, preferred int) int {
+	if preferred != 0 {
+		return preferred
+	}
+	if n, err := strconv.Atoi(key); err == nil {
+		return n
+	}
+	if len(key) == 3 && strings.HasSuffix(strings.ToUpper(key), "XX") && key[0] >= '1' && key[0] <= '5' {
+		return int(key[0]-'0') * 100
 	}
+	return 200
+}
 
-	// Try common JSON-like content types
-	for _, ct := range []string{"application/json", "application/problem+json", "*/*"} {
-		mt := resp.Content.Get(ct)
-		if mt == nil {
-			continue
-		}
+func statusAllowsBody(status int) bool {
+	return status >= 200 && status != 204 && status != 304
+}
 
-		// MediaType.Example
-		if mt.Example != nil {
-			if b, err := json.Marshal(mt.Example); err == nil {
-				return b, true
-			}
+// responseHeaders builds the declared response headers from their examples or schemas.
+func responseHeaders(resp *openapi3.Response, g *schemaGenerator) map[string]string {
+	out := map[string]string{}
+	names := make([]string, 0, len(resp.Headers))
+	for name := range resp.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		h := resp.Headers[name]
+		// Content-Type follows the media type, never a header declaration
+		if h == nil || h.Value == nil || strings.EqualFold(name, "content-type") {
+			continue
 		}
 
-		if len(mt.Examples) > 0 {
-			for _, exRef := range mt.Examples {
-				if exRef == nil || exRef.Value == nil {
-					continue
-				}
-				if exRef.Value.Value != nil {
-					if b, err := json.Marshal(exRef.Value.Value); err == nil {
-						return b, true
-					}
+		var v any
+		switch {
+		case h.Value.Example != nil:
+			v = h.Value.Example
+		case len(h.Value.Examples) > 0:
+			for _, exName := range sortedKeys(h.Value.Examples) {
+				if ex := h.Value.Examples[exName]; ex != nil && ex.Value != nil && ex.Value.Value != nil {
+					v = ex.Value.Value
+					break
 				}
 			}
+		case h.Value.Schema != nil:
+			v = g.generate(h.Value.Schema)
+		}
+		if v != nil {
+			out[name] = headerString(v)
 		}
 	}
-
-	return nil, false
+	return out
 }
 
-func (p *SpecProvider) generateFromResponseSchema(resp *openapi3.Response) ([]byte, bool) {
-	if resp == nil || resp.Content == nil {
-		return nil, false
+// headerString formats a value in the simple style of header parameters.
+func headerString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []any:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = headerString(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
 	}
+	return string(b)
+}
 
-	for _, ct := range []string{"application/json", "application/problem+json", "*/*"} {
-		mt := resp.Content.Get(ct)
-		if mt == nil || mt.Schema == nil {
-			continue
+// mediaTypes lists the media types of a response: JSON types first, application/json
+// leading and the */* wildcard last among them, then all other types by name.
+func mediaTypes(content openapi3.Content) []string {
+	var out []string
+	for _, ct := range []string{"application/json", "application/problem+json"} {
+		if content[ct] != nil {
+			out = append(out, ct)
 		}
+	}
+	var jsonTypes, other []string
+	for ct := range content {
+		switch {
+		case ct == "application/json", ct == "application/problem+json", ct == "*/*":
+		case isJSONMediaType(ct):
+			jsonTypes = append(jsonTypes, ct)
+		default:
+			other = append(other, ct)
+		}
+	}
+	sort.Strings(jsonTypes)
+	sort.Strings(other)
+	out = append(out, jsonTypes...)
+	if content["*/*"] != nil {
+		out = append(out, "*/*")
+	}
+	return append(out, other...)
+}
 
-		val := p.genFromSchemaRef(mt.Schema, map[string]bool{}, 0)
-		b, err := json.Marshal(val)
-		return b, err == nil
+func hasJSONMediaType(content openapi3.Content) bool {
+	for ct := range content {
+		if isJSONMediaType(ct) || ct == "*/*" {
+			return true
+		}
 	}
+	return false
+}
 
-	return nil, false
+func isJSONMediaType(ct string) bool {
+	base, _, _ := strings.Cut(strings.ToLower(ct), ";")
+	base = strings.TrimSpace(base)
+	return base == "application/json" || strings.HasSuffix(base, "+json")
 }
 
-func (p *SpecProvider) genFromSchemaRef(ref *openapi3.SchemaRef, visiting map[string]bool, depth int) any {
-	if depth > 6 || ref == nil || ref.Value == nil {
-		return map[string]any{}
+// encodeBody serializes a value for media type ct: JSON for JSON types and
+// wildcards, the string itself for text, XML, CSV or binary types.
+func encodeBody(ct string, v any) ([]byte, bool) {
+	if isJSONMediaType(ct) || strings.Contains(ct, "*") {
+		b, err := json.Marshal(v)
+		return b, err == nil
 	}
+	str, ok := v.(string)
+	return []byte(str), ok
+}
 
-	s := ref.Value
+// servedContentType is the Content-Type for a media type key; wildcards are served as JSON.
+func servedContentType(ct string) string {
+	if strings.Contains(ct, "*") {
+		return "application/json"
+	}
+	return ct
+}
 
-	// enum wins
-	if len(s.Enum) > 0 {
-		return s.Enum[0]
+// extractExampleFromResponse returns the named example of a media type and the
+// content type to serve it with. Without a name it returns the media type's example,
+// or else its first example by name. Non-JSON media types need string examples.
+func (p *SpecProvider) extractExampleFromResponse(resp *openapi3.Response, name string) (string, []byte, bool) {
+	if resp == nil || resp.Content == nil {
+		return "", nil, false
 	}
 
-	// ARRAY
-	if s.Type != nil && s.Type.Is("array") {
-		if s.Items == nil {
-			return []any{}
+	for _, ct := range mediaTypes(resp.Content) {
+		mt := resp.Content[ct]
+
+		if name != "" {
+			if exRef := mt.Examples[name]; exRef != nil && exRef.Value != nil && exRef.Value.Value != nil {
+				if b, ok := encodeBody(ct, exRef.Value.Value); ok {
+					return servedContentType(ct), b, true
+				}
+			}
+			continue
 		}
-		return []any{p.genFromSchemaRef(s.Items, visiting, depth+1)}
-	}
 
-	// OBJECT
-	if (s.Type != nil && s.Type.Is("object")) || len(s.Properties) > 0 || s.AdditionalProperties.Schema != nil {
-		return p.genObject(s, visiting, depth)
-	}
+		// MediaType.Example
+		if mt.Example != nil {
+			if b, ok := encodeBody(ct, mt.Example); ok {
+				return servedContentType(ct), b, true
+			}
+		}
 
-	// PRIMITIVES
-	if s.Type != nil && s.Type.Is("string") {
-		if s.Format == "date-time" {
-			return "2026-01-28T00:00:00Z"
+		for _, exName := range sortedKeys(mt.Examples) {
+			exRef := mt.Examples[exName]
+			if exRef == nil || exRef.Value == nil || exRef.Value.Value == nil {
+				continue
+			}
+			if b, ok := encodeBody(ct, exRef.Value.Value); ok {
+				return servedContentType(ct), b, true
+			}
 		}
-		return "string"
-	}
-	if s.Type != nil && s.Type.Is("integer") {
-		return 0
-	}
-	if s.Type != nil && s.Type.Is("number") {
-		return 0.0
-	}
-	if s.Type != nil && s.Type.Is("boolean") {
-		return true
 	}
 
-	// fallback
-	return map[string]any{"ok": true}
+	return "", nil, false
 }
 
-func (p *SpecProvider) genObject(s *openapi3.Schema, visiting map[string]bool, depth int) any {
-	out := map[string]any{}
-
-	// additionalProperties: schema form
-	if s.AdditionalProperties.Schema != nil {
-		out["key"] = p.genFromSchemaRef(s.AdditionalProperties.Schema, visiting, depth+1)
-		return out
+// sortedKeys lists the keys of a map in a stable order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+	return keys
+}
 
-	if s.AdditionalProperties.Has != nil && *s.AdditionalProperties.Has {
-		out["key"] = "value"
+func (p *SpecProvider) generateFromResponseSchema(resp *openapi3.Response, g *schemaGenerator) (string, []byte, bool) {
+	if resp == nil || resp.Content == nil {
+		return "", nil, false
 	}
 
-	// properties
-	for name, prop := range s.Properties {
-		out[name] = p.genFromSchemaRef(prop, visiting, depth+1)
+	for _, ct := range mediaTypes(resp.Content) {
+		mt := resp.Content[ct]
+		if mt == nil || mt.Schema == nil {
+			continue
+		}
+
+		// non-JSON media types only take generated strings, e.g. format: binary
+		if b, ok := encodeBody(ct, g.generate(mt.Schema)); ok {
+			return servedContentType(ct), b, true
+		}
 	}
 
-	return out
+	return "", nil, false
 }
@@ -8,10 +8,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadSpec_OpenAPI3_OK(t *testing.T) {
@@ -94,6 +96,81 @@ func TestLoadSpec_Swagger2_OK(t *testing.T) {
 	}
 }
 
+func TestLoadSpec_YAML(t *testing.T) {
+	oas3 := `openapi: 3.0.3
+info: {title: t, version: "1"}
+paths:
+  /health:
+    get:
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Health'}
+components:
+  schemas:
+    Health:
+      type: object
+      properties:
+        ok: {type: boolean}
+`
+	swagger2 := `swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /health:
+    get:
+      produces: [application/json]
+      responses:
+        "200":
+          description: ok
+          schema: {$ref: '#/definitions/Health'}
+definitions:
+  Health:
+    type: object
+    properties:
+      ok: {type: boolean}
+`
+	cases := []struct {
+		name, file, content string
+	}{
+		{"openapi3 .yaml", "openapi.yaml", oas3},
+		{"swagger2 .yml", "swagger.yml", swagger2},
+		{"sniffed without extension", "spec", oas3},
+		{"sniffed with .json extension", "swagger.json", swagger2},
+		{"unquoted swagger 2.0", "swagger.yaml", strings.Replace(swagger2, `swagger: "2.0"`, "swagger: 2.0", 1)},
+		{"swagger 2", "swagger.yaml", strings.Replace(swagger2, `swagger: "2.0"`, "swagger: 2", 1)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(p, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			provider, err := NewSpecProvider(p, logrus.New())
+			if err != nil {
+				t.Fatalf("NewSpecProvider: %v", err)
+			}
+			body, ok := exampleBody(provider, "/health", "GET", nil, Preference{})
+			if !ok || string(body) != `{"ok":true}` {
+				t.Fatalf("expected body generated from the resolved $ref, got %s", body)
+			}
+		})
+	}
+}
+
+func TestLoadSpec_InvalidYAML(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(p, []byte("openapi: [3.0\n  paths: :"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if _, err := NewSpecProvider(p, logrus.New()); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
 func TestLoadSpec_InvalidJSON(t *testing.T) {
 	dir := t.TempDir()
 	p := filepath.Join(dir, "bad.json")
@@ -102,8 +179,8 @@ func TestLoadSpec_InvalidJSON(t *testing.T) {
 	}
 
 	_, err := NewSpecProvider(p, logrus.New())
-	if err == nil {
-		t.Fatalf("expected error")
+	if err == nil || !strings.Contains(err.Error(), "parse spec json") {
+		t.Fatalf("expected the version probe to fail, got %v", err)
 	}
 }
 
@@ -123,7 +200,7 @@ func TestPickBestResponseRef_Prefers200Then201202204(t *testing.T) {
 	resps.Set("202", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("accepted")}})
 	resps.Set("204", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("nocontent")}})
 
-	got := p.pickBestResponseRef(resps)
+	_, got := p.pickBestResponseRef(resps)
 	if got == nil || got.Value == nil || got.Value.Description == nil || *got.Value.Description != "ok" {
 		t.Fatalf("expected 200, got %#v", got)
 	}
@@ -131,7 +208,7 @@ func TestPickBestResponseRef_Prefers200Then201202204(t *testing.T) {
 	resps = openapi3.NewResponses()
 	resps.Set("201", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("created")}})
 	resps.Set("202", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("accepted")}})
-	got = p.pickBestResponseRef(resps)
+	_, got = p.pickBestResponseRef(resps)
 	if got == nil || got.Value == nil || got.Value.Description == nil || *got.Value.Description != "created" {
 		t.Fatalf("expected 201, got %#v", got)
 	}
@@ -145,7 +222,7 @@ func TestPickBestResponseRef_PicksLowest2xx(t *testing.T) {
 	resps.Set("250", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("250")}})
 	resps.Set("210", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("210")}})
 
-	got := p.pickBestResponseRef(resps)
+	_, got := p.pickBestResponseRef(resps)
 	if got == nil || got.Value == nil || got.Value.Description == nil || *got.Value.Description != "210" {
 		t.Fatalf("expected lowest 2xx=210, got %#v", got)
 	}
@@ -160,7 +237,7 @@ func TestExtractExampleFromResponse_Example(t *testing.T) {
 		},
 	}
 
-	b, ok := p.extractExampleFromResponse(resp)
+	_, b, ok := p.extractExampleFromResponse(resp, "")
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -185,7 +262,7 @@ func TestExtractExampleFromResponse_ExamplesMap(t *testing.T) {
 		},
 	}
 
-	b, ok := p.extractExampleFromResponse(resp)
+	_, b, ok := p.extractExampleFromResponse(resp, "")
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -196,7 +273,7 @@ func TestExtractExampleFromResponse_ExamplesMap(t *testing.T) {
 	}
 }
 
-func TestExtractExampleFromResponse_NoJSONContent(t *testing.T) {
+func TestExtractExampleFromResponse_NonJSONContent(t *testing.T) {
 	p := &SpecProvider{log: logrus.New()}
 
 	resp := &openapi3.Response{
@@ -204,19 +281,29 @@ func TestExtractExampleFromResponse_NoJSONContent(t *testing.T) {
 			"text/plain": &openapi3.MediaType{Example: "hi"},
 		},
 	}
-	_, ok := p.extractExampleFromResponse(resp)
-	if ok {
-		t.Fatalf("expected false for non-json content types")
+	ct, b, ok := p.extractExampleFromResponse(resp, "")
+	if !ok || ct != "text/plain" || string(b) != "hi" {
+		t.Fatalf("expected the text example as is, got %q %q (ok=%v)", ct, b, ok)
+	}
+
+	// structured examples cannot be served as XML
+	resp = &openapi3.Response{
+		Content: openapi3.Content{
+			"application/xml": &openapi3.MediaType{Example: map[string]any{"id": 1}},
+		},
+	}
+	if _, _, ok := p.extractExampleFromResponse(resp, ""); ok {
+		t.Fatalf("expected false for a non-string XML example")
 	}
 }
 
 func TestExtractExampleFromResponse_NilGuards(t *testing.T) {
 	p := &SpecProvider{log: logrus.New()}
 
-	if _, ok := p.extractExampleFromResponse(nil); ok {
+	if _, _, ok := p.extractExampleFromResponse(nil, ""); ok {
 		t.Fatalf("expected false")
 	}
-	if _, ok := p.extractExampleFromResponse(&openapi3.Response{}); ok {
+	if _, _, ok := p.extractExampleFromResponse(&openapi3.Response{}, ""); ok {
 		t.Fatalf("expected false")
 	}
 }
@@ -237,7 +324,7 @@ func TestGenerateFromResponseSchema_JSON(t *testing.T) {
 		},
 	}
 
-	b, ok := p.generateFromResponseSchema(resp)
+	_, b, ok := p.generateFromResponseSchema(resp, newSchemaGenerator())
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -258,7 +345,7 @@ func TestGenerateFromResponseSchema_ProblemJSON(t *testing.T) {
 			},
 		},
 	}
-	b, ok := p.generateFromResponseSchema(resp)
+	_, b, ok := p.generateFromResponseSchema(resp, newSchemaGenerator())
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -279,7 +366,7 @@ func TestGenerateFromResponseSchema_StarStar(t *testing.T) {
 			},
 		},
 	}
-	b, ok := p.generateFromResponseSchema(resp)
+	_, b, ok := p.generateFromResponseSchema(resp, newSchemaGenerator())
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -298,7 +385,7 @@ func TestGenerateFromResponseSchema_NoSchema(t *testing.T) {
 			"application/json": &openapi3.MediaType{},
 		},
 	}
-	_, ok := p.generateFromResponseSchema(resp)
+	_, _, ok := p.generateFromResponseSchema(resp, newSchemaGenerator())
 	if ok {
 		t.Fatalf("expected false")
 	}
@@ -307,167 +394,33 @@ func TestGenerateFromResponseSchema_NoSchema(t *testing.T) {
 func TestGenerateFromResponseSchema_NilGuards(t *testing.T) {
 	p := &SpecProvider{log: logrus.New()}
 
-	if _, ok := p.generateFromResponseSchema(nil); ok {
+	if _, _, ok := p.generateFromResponseSchema(nil, newSchemaGenerator()); ok {
 		t.Fatalf("expected false")
 	}
-	if _, ok := p.generateFromResponseSchema(&openapi3.Response{}); ok {
+	if _, _, ok := p.generateFromResponseSchema(&openapi3.Response{}, newSchemaGenerator()); ok {
 		t.Fatalf("expected false")
 	}
 }
 
-func TestGenFromSchemaRef_EnumWins(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	v := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
-		Enum: []any{"a", "b"},
-	}}, map[string]bool{}, 0)
-
-	if v != "a" {
-		t.Fatalf("expected first enum, got %#v", v)
-	}
-}
-
-func TestGenFromSchemaRef_Primitives(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	tests := []struct {
-		name string
-		s    *openapi3.Schema
-		want any
-	}{
-		{"string", &openapi3.Schema{Type: &openapi3.Types{"string"}}, "string"},
-		{"datetime", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "date-time"}, "2026-01-28T00:00:00Z"},
-		{"integer", &openapi3.Schema{Type: &openapi3.Types{"integer"}}, 0},
-		{"number", &openapi3.Schema{Type: &openapi3.Types{"number"}}, 0.0},
-		{"boolean", &openapi3.Schema{Type: &openapi3.Types{"boolean"}}, true},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: tc.s}, map[string]bool{}, 0)
-			if got != tc.want {
-				t.Fatalf("got %#v want %#v", got, tc.want)
-			}
-		})
-	}
-}
-
-func TestGenFromSchemaRef_Array(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
-		Type:  &openapi3.Types{"array"},
-		Items: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
-	}}, map[string]bool{}, 0)
-
-	arr, ok := got.([]any)
-	if !ok || len(arr) != 1 || arr[0] != "string" {
-		t.Fatalf("unexpected: %#v", got)
-	}
-}
-
-func TestGenFromSchemaRef_ObjectProperties(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
-		Type: &openapi3.Types{"object"},
-		Properties: openapi3.Schemas{
-			"id":   {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
-			"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
-		},
-	}}, map[string]bool{}, 0)
-
-	m, ok := got.(map[string]any)
-	if !ok || m["id"] != 0 || m["name"] != "string" {
-		t.Fatalf("unexpected: %#v", got)
-	}
-}
-
-func TestGenObject_AdditionalPropertiesSchema(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	s := &openapi3.Schema{}
-	s.AdditionalProperties.Schema = &openapi3.SchemaRef{
-		Value: &openapi3.Schema{Type: &openapi3.Types{"string"}},
-	}
-
-	got := p.genObject(s, map[string]bool{}, 0)
-	m, ok := got.(map[string]any)
-	if !ok || m["key"] != "string" {
-		t.Fatalf("unexpected: %#v", got)
-	}
-}
-
-func TestGenObject_AdditionalPropertiesTrue(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	s := &openapi3.Schema{
-		Properties: openapi3.Schemas{
-			"a": {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
-		},
-	}
-	b := true
-	s.AdditionalProperties.Has = &b
-
-	got := p.genObject(s, map[string]bool{}, 0)
-	m, ok := got.(map[string]any)
-	if !ok {
-		t.Fatalf("unexpected: %#v", got)
-	}
-	if m["a"] != 0 {
-		t.Fatalf("expected property a, got %#v", m)
-	}
-	if m["key"] != "value" {
-		t.Fatalf("expected additionalProperties placeholder, got %#v", m)
-	}
-}
-
-func TestGenFromSchemaRef_DepthLimit(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
-		Type: &openapi3.Types{"object"},
-	}}, map[string]bool{}, 7)
-
-	m, ok := got.(map[string]any)
-	if !ok || len(m) != 0 {
-		t.Fatalf("expected empty map due to depth limit, got %#v", got)
-	}
-}
-
-func TestGenFromSchemaRef_NilGuards(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	got := p.genFromSchemaRef(nil, map[string]bool{}, 0)
-	if _, ok := got.(map[string]any); !ok {
-		t.Fatalf("expected map fallback, got %#v", got)
-	}
-
-	got = p.genFromSchemaRef(&openapi3.SchemaRef{Value: nil}, map[string]bool{}, 0)
-	if _, ok := got.(map[string]any); !ok {
-		t.Fatalf("expected map fallback, got %#v", got)
-	}
-}
-
-func TestTryGetExampleBody_NoOperation(t *testing.T) {
+func TestTryGetExampleResponse_NoOperation(t *testing.T) {
 	p := &SpecProvider{
 		spec: &Spec{Doc3: &openapi3.T{}},
 		log:  logrus.New(),
 	}
 
-	_, ok := p.TryGetExampleBody("/missing", "get")
+	_, ok := exampleBody(p, "/missing", "get", nil, Preference{})
 	if ok {
 		t.Fatalf("expected false when operation not found or responses nil")
 	}
 }
 
-func TestTryGetExampleBody_ResponseMissingValue_FallbackOK(t *testing.T) {
+func TestTryGetExampleResponse_ResponseMissingValue_StatusOnly(t *testing.T) {
 	paths := openapi3.NewPaths()
 	paths.Set("/x", &openapi3.PathItem{
 		Get: &openapi3.Operation{
 			Responses: func() *openapi3.Responses {
 				r := openapi3.NewResponses()
-				r.Set("200", &openapi3.ResponseRef{})
+				r.Set("202", &openapi3.ResponseRef{})
 				return r
 			}(),
 		},
@@ -480,19 +433,14 @@ func TestTryGetExampleBody_ResponseMissingValue_FallbackOK(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/x", "get")
-	if !ok {
-		t.Fatalf("expected ok")
-	}
-
-	var m map[string]any
-	_ = json.Unmarshal(b, &m)
-	if m["ok"] != true {
-		t.Fatalf("unexpected: %#v", m)
-	}
+	ex, ok := p.TryGetExampleResponse("/x", "get", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, 202, ex.Status)
+	require.Empty(t, ex.ContentType)
+	require.Empty(t, ex.Body)
 }
 
-func TestTryGetExampleBody_ExplicitExampleWins(t *testing.T) {
+func TestTryGetExampleResponse_ExplicitExampleWins(t *testing.T) {
 	paths := openapi3.NewPaths()
 	paths.Set("/x", &openapi3.PathItem{
 		Get: &openapi3.Operation{
@@ -519,7 +467,7 @@ func TestTryGetExampleBody_ExplicitExampleWins(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/x", "get")
+	b, ok := exampleBody(p, "/x", "get", nil, Preference{})
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -531,7 +479,45 @@ func TestTryGetExampleBody_ExplicitExampleWins(t *testing.T) {
 	}
 }
 
-func TestTryGetExampleBody_SchemaGeneratedWhenNoExample(t *testing.T) {
+func TestHasExample(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/example", &openapi3.PathItem{
+		Get: &openapi3.Operation{Responses: openapi3.NewResponses(
+			openapi3.WithStatus(200, &openapi3.ResponseRef{Value: &openapi3.Response{
+				Content: openapi3.Content{"application/json": &openapi3.MediaType{Example: []any{"a"}}},
+			}}),
+		)},
+	})
+	paths.Set("/schema", &openapi3.PathItem{
+		Get: &openapi3.Operation{Responses: openapi3.NewResponses(
+			openapi3.WithStatus(200, &openapi3.ResponseRef{Value: &openapi3.Response{
+				Content: openapi3.NewContentWithJSONSchema(openapi3.NewStringSchema()),
+			}}),
+		)},
+	})
+	paths.Set("/empty", &openapi3.PathItem{
+		Delete: &openapi3.Operation{Responses: openapi3.NewResponses(
+			openapi3.WithStatus(204, &openapi3.ResponseRef{Value: &openapi3.Response{}}),
+		)},
+	})
+
+	p := &SpecProvider{spec: &Spec{Doc3: &openapi3.T{Paths: paths}}, log: logrus.New()}
+
+	if !p.HasExample("/example", "get") {
+		t.Fatalf("expected explicit example to count")
+	}
+	if p.HasExample("/schema", "get") {
+		t.Fatalf("expected schema-only response not to count")
+	}
+	if !p.HasExample("/empty", "delete") {
+		t.Fatalf("expected response without content to count")
+	}
+	if p.HasExample("/missing", "get") {
+		t.Fatalf("expected missing operation not to count")
+	}
+}
+
+func TestTryGetExampleResponse_SchemaGeneratedWhenNoExample(t *testing.T) {
 	paths := openapi3.NewPaths()
 	paths.Set("/x", &openapi3.PathItem{
 		Get: &openapi3.Operation{
@@ -563,7 +549,7 @@ func TestTryGetExampleBody_SchemaGeneratedWhenNoExample(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/x", "get")
+	b, ok := exampleBody(p, "/x", "get", nil, Preference{})
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -575,7 +561,7 @@ func TestTryGetExampleBody_SchemaGeneratedWhenNoExample(t *testing.T) {
 	}
 }
 
-func TestTryGetExampleBody_FallbackOk(t *testing.T) {
+func TestTryGetExampleResponse_NoResponses_EmptyOK(t *testing.T) {
 	paths := openapi3.NewPaths()
 	paths.Set("/health", &openapi3.PathItem{
 		Get: &openapi3.Operation{
@@ -589,16 +575,38 @@ func TestTryGetExampleBody_FallbackOk(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/health", "get")
-	if !ok {
-		t.Fatalf("expected ok")
-	}
+	ex, ok := p.TryGetExampleResponse("/health", "get", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, 200, ex.Status)
+	require.Empty(t, ex.ContentType)
+	require.Empty(t, ex.Body)
+}
 
-	var m map[string]any
-	_ = json.Unmarshal(b, &m)
-	if m["ok"] != true {
-		t.Fatalf("unexpected: %#v", m)
+func TestTryGetExampleResponse_JSONWithoutExampleOrSchema_EmptyBody(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/scans", &openapi3.PathItem{
+		Post: &openapi3.Operation{
+			Responses: func() *openapi3.Responses {
+				r := openapi3.NewResponses()
+				r.Set("201", &openapi3.ResponseRef{Value: &openapi3.Response{
+					Description: ptr("created"),
+					Content:     openapi3.Content{"application/json": &openapi3.MediaType{}},
+				}})
+				return r
+			}(),
+		},
+	})
+
+	p := &SpecProvider{
+		spec: &Spec{Doc3: &openapi3.T{Paths: paths}},
+		log:  logrus.New(),
 	}
+
+	ex, ok := p.TryGetExampleResponse("/scans", "post", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, 201, ex.Status)
+	require.Empty(t, ex.ContentType)
+	require.Empty(t, ex.Body)
 }
 
 func TestNewSpecProvider_GetSpec_OpenAPI3(t *testing.T) {
@@ -643,3 +651,195 @@ func TestNewSpecProvider_GetSpec_OpenAPI3(t *testing.T) {
 }
 
 func ptr(s string) *string { return &s }
+
+func TestTryGetStatusExampleBody_ExactStatusOnly(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/x/{id}", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			Responses: func() *openapi3.Responses {
+				r := openapi3.NewResponses()
+				r.Set("200", &openapi3.ResponseRef{
+					Value: &openapi3.Response{
+						Content: openapi3.Content{
+							"application/json": &openapi3.MediaType{Example: map[string]any{"id": "1"}},
+						},
+					},
+				})
+				r.Set("404", &openapi3.ResponseRef{
+					Value: &openapi3.Response{
+						Content: openapi3.Content{
+							"application/json": &openapi3.MediaType{Example: map[string]any{"code": "not_found"}},
+						},
+					},
+				})
+				return r
+			}(),
+		},
+	})
+
+	p := &SpecProvider{
+		spec: &Spec{Doc3: &openapi3.T{Paths: paths}},
+		log:  logrus.New(),
+	}
+
+	b, ok := p.TryGetStatusExampleBody("/x/{id}", "get", 404, nil)
+	if !ok || string(b) != `{"code":"not_found"}` {
+		t.Fatalf("expected 404 example, got %s (ok=%v)", b, ok)
+	}
+
+	if _, ok := p.TryGetStatusExampleBody("/x/{id}", "get", 409, nil); ok {
+		t.Fatalf("expected no body for an undeclared status")
+	}
+}
+
+func TestTryGetExampleResponse_Preference(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans/{id}":{
+		  "get":{
+			"responses":{
+			  "200":{"description":"ok","content":{"application/json":{
+				"schema":{"type":"object","properties":{"id":{"type":"string","format":"uuid"}}},
+				"examples":{
+				  "running":{"value":{"status":"running"}},
+				  "finished":{"value":{"status":"finished"}}
+				}
+			  }}},
+			  "404":{"description":"missing","content":{"application/json":{"example":{"error":"not found"}}}},
+			  "5XX":{"description":"broken","content":{"application/json":{"example":{"error":"server"}}}}
+			}
+		  }
+		},
+		"/health":{
+		  "get":{
+			"responses":{
+			  "200":{"description":"ok","content":{"application/json":{"example":{"ok":true}}}},
+			  "default":{"description":"error","content":{"application/json":{"example":{"error":"default"}}}}
+			}
+		  }
+		}
+	  }
+	}`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p := &SpecProvider{spec: &Spec{Doc3: doc}, log: logrus.New()}
+
+	cases := []struct {
+		name string
+		path string
+		pref Preference
+		want string
+		ok   bool
+	}{
+		{"first example by name", "/scans/{id}", Preference{}, `{"status":"finished"}`, true},
+		{"named example", "/scans/{id}", Preference{Example: "running"}, `{"status":"running"}`, true},
+		{"unknown example", "/scans/{id}", Preference{Example: "paused"}, "", false},
+		{"status code", "/scans/{id}", Preference{Code: 404}, `{"error":"not found"}`, true},
+		{"status range", "/scans/{id}", Preference{Code: 503}, `{"error":"server"}`, true},
+		{"undeclared code", "/scans/{id}", Preference{Code: 409}, "", false},
+		{"default response", "/health", Preference{Code: 409}, `{"error":"default"}`, true},
+		{"dynamic", "/scans/{id}", Preference{Dynamic: true}, `{"id":"3fa85f64-5717-4562-b3fc-2c963f66afa6"}`, true},
+		{"dynamic without schema", "/health", Preference{Dynamic: true}, `{"ok":true}`, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, ok := exampleBody(p, tc.path, "get", nil, tc.pref)
+			if ok != tc.ok || (ok && string(b) != tc.want) {
+				t.Fatalf("got %s (ok=%v), want %s (ok=%v)", b, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+// exampleBody returns only the body of a spec response.
+func exampleBody(p ISpecProvider, swaggerPath, method string, params map[string]string, pref Preference) ([]byte, bool) {
+	resp, ok := p.TryGetExampleResponse(swaggerPath, method, params, pref)
+	if !ok {
+		return nil, false
+	}
+	return resp.Body, true
+}
+
+func TestTryGetExampleResponse_StatusHeadersAndMediaType(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans":{
+		  "post":{
+			"responses":{
+			  "201":{
+				"description":"created",
+				"headers":{
+				  "Location":{"schema":{"type":"string"},"example":"/scans/1"},
+				  "X-Rate-Limit":{"schema":{"type":"integer","minimum":10}},
+				  "Content-Type":{"schema":{"type":"string"},"example":"text/html"}
+				},
+				"content":{"application/json":{"example":{"id":"1"}}}
+			  }
+			}
+		  },
+		  "delete":{"responses":{"204":{"description":"gone","content":{"application/json":{"example":{"ok":true}}}}}}
+		},
+		"/scans/{id}":{
+		  "put":{"responses":{"202":{"description":"accepted"}}},
+		  "get":{"responses":{"4XX":{"description":"error","content":{"application/problem+json":{"example":{"title":"bad"}}}}}}
+		},
+		"/version":{
+		  "get":{"responses":{"200":{"description":"ok","content":{"text/plain":{"example":"1.2.3"}}}}}
+		},
+		"/reports/{id}":{
+		  "get":{"responses":{"200":{"description":"ok","content":{
+			"application/xml":{"schema":{"type":"object"}},
+			"application/pdf":{"schema":{"type":"string","format":"binary"}}
+		  }}}}
+		},
+		"/reports/{id}/raw":{
+		  "get":{"responses":{"200":{"description":"ok","content":{"application/octet-stream":{"schema":{"type":"object"}}}}}}
+		}
+	  }
+	}`))
+	require.NoError(t, err)
+	p := &SpecProvider{spec: &Spec{Doc3: doc}, log: logrus.New()}
+
+	resp, ok := p.TryGetExampleResponse("/scans", "post", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, 201, resp.Status)
+	require.Equal(t, "application/json", resp.ContentType)
+	require.JSONEq(t, `{"id":"1"}`, string(resp.Body))
+	require.Equal(t, map[string]string{"Location": "/scans/1", "X-Rate-Limit": "10"}, resp.Headers)
+
+	resp, ok = p.TryGetExampleResponse("/scans", "delete", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, 204, resp.Status)
+	require.Empty(t, resp.ContentType)
+	require.Empty(t, resp.Body)
+
+	resp, ok = p.TryGetExampleResponse("/scans/{id}", "put", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, 202, resp.Status)
+	require.Empty(t, resp.Body)
+
+	resp, ok = p.TryGetExampleResponse("/scans/{id}", "get", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, 400, resp.Status)
+	require.Equal(t, "application/problem+json", resp.ContentType)
+
+	resp, ok = p.TryGetExampleResponse("/version", "get", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, "text/plain", resp.ContentType)
+	require.Equal(t, "1.2.3", string(resp.Body))
+
+	resp, ok = p.TryGetExampleResponse("/reports/{id}", "get", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, "application/pdf", resp.ContentType, "the XML object schema cannot be generated")
+	require.Equal(t, "string", string(resp.Body))
+
+	resp, ok = p.TryGetExampleResponse("/reports/{id}/raw", "get", nil, Preference{})
+	require.True(t, ok)
+	require.Equal(t, "application/octet-stream", resp.ContentType)
+	require.Empty(t, resp.Body)
+}
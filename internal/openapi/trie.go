@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// routeNode is one path segment of the route trie. Literal children are tried
+// before parameter children, and constrained parameters before free ones, so a
+// request always resolves to the same route however the spec orders its paths.
+type routeNode struct {
+	literals map[string]*routeNode
+	params   []*paramEdge
+	// routes indexes RouterProvider.routes by method for templates ending here.
+	routes map[string]int
+}
+
+type paramEdge struct {
+	name  string
+	check *paramCheck
+	node  *routeNode
+}
+
+// paramCheck holds the constraints of a path parameter's schema.
+type paramCheck struct {
+	typ     string
+	format  string
+	enum    map[string]bool
+	pattern *regexp.Regexp
+}
+
+func newRouteNode() *routeNode {
+	return &routeNode{literals: map[string]*routeNode{}, routes: map[string]int{}}
+}
+
+// insert adds the route at index i below n.
+func (n *routeNode) insert(segments []string, checks map[string]*paramCheck, method string, i int) {
+	for _, seg := range segments {
+		name, isParam := paramName(seg)
+		if !isParam {
+			child, ok := n.literals[seg]
+			if !ok {
+				child = newRouteNode()
+				n.literals[seg] = child
+			}
+			n = child
+			continue
+		}
+
+		check := checks[name]
+		var edge *paramEdge
+		for _, e := range n.params {
+			if e.name == name && e.check.key() == check.key() {
+				edge = e
+				break
+			}
+		}
+		if edge == nil {
+			edge = &paramEdge{name: name, check: check, node: newRouteNode()}
+			n.params = append(n.params, edge)
+			sort.SliceStable(n.params, func(a, b int) bool {
+				ca, cb := n.params[a].check.constrained(), n.params[b].check.constrained()
+				if ca != cb {
+					return ca
+				}
+				return n.params[a].name < n.params[b].name
+			})
+		}
+		n = edge.node
+	}
+	if _, ok := n.routes[method]; !ok {
+		n.routes[method] = i
+	}
+}
+
+// match walks the trie depth first. It returns the route index for method and
+// the parameter values collected on the way, or -1. loose ignores the parameter
+// schemas.
+func (n *routeNode) match(segments []string, method string, params map[string]string, loose bool) int {
+	if len(segments) == 0 {
+		if i, ok := n.routes[method]; ok {
+			return i
+		}
+		return -1
+	}
+
+	seg, rest := segments[0], segments[1:]
+	if child, ok := n.literals[seg]; ok {
+		if i := child.match(rest, method, params, loose); i >= 0 {
+			return i
+		}
+	}
+	if seg == "" {
+		return -1
+	}
+	for _, e := range n.params {
+		if !loose && !e.check.accepts(seg) {
+			continue
+		}
+		if i := e.node.match(rest, method, params, loose); i >= 0 {
+			params[e.name] = seg
+			return i
+		}
+	}
+	return -1
+}
+
+// methods adds the methods of every template matching segments to out.
+func (n *routeNode) methods(segments []string, out map[string]bool) {
+	if len(segments) == 0 {
+		for m := range n.routes {
+			out[m] = true
+		}
+		return
+	}
+
+	seg, rest := segments[0], segments[1:]
+	if child, ok := n.literals[seg]; ok {
+		child.methods(rest, out)
+	}
+	if seg == "" {
+		return
+	}
+	for _, e := range n.params {
+		if e.check.accepts(seg) {
+			e.node.methods(rest, out)
+		}
+	}
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func paramName(seg string) (string, bool) {
+	if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+		return strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "}"), true
+	}
+	return "", false
+}
+
+// pathParamChecks collects the schema constraints of a path item's path parameters.
+// Operation-level declarations win over path-level ones.
+func pathParamChecks(item *openapi3.PathItem) map[string]*paramCheck {
+	out := map[string]*paramCheck{}
+	add := func(params openapi3.Parameters) {
+		for _, ref := range params {
+			if ref == nil || ref.Value == nil || ref.Value.In != openapi3.ParameterInPath {
+				continue
+			}
+			if _, ok := out[ref.Value.Name]; ok {
+				continue
+			}
+			if ref.Value.Schema != nil {
+				out[ref.Value.Name] = newParamCheck(ref.Value.Schema.Value)
+			}
+		}
+	}
+
+	methods := make([]string, 0, len(item.Operations()))
+	for m := range item.Operations() {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	for _, m := range methods {
+		add(item.Operations()[m].Parameters)
+	}
+	add(item.Parameters)
+	return out
+}
+
+func newParamCheck(s *openapi3.Schema) *paramCheck {
+	if s == nil {
+		return nil
+	}
+
+	c := &paramCheck{format: s.Format}
+	if s.Type != nil && len(*s.Type) == 1 {
+		c.typ = (*s.Type)[0]
+	}
+	if len(s.Enum) > 0 {
+		c.enum = map[string]bool{}
+		for _, v := range s.Enum {
+			c.enum[fmt.Sprint(v)] = true
+		}
+	}
+	if s.Pattern != "" {
+		// an invalid pattern does not constrain the route
+		c.pattern, _ = regexp.Compile(s.Pattern)
+	}
+	if !c.constrained() {
+		return nil
+	}
+	return c
+}
+
+func (c *paramCheck) constrained() bool {
+	if c == nil {
+		return false
+	}
+	return c.typ == "integer" || c.typ == "number" || c.typ == "boolean" ||
+		c.format == "uuid" || c.enum != nil || c.pattern != nil
+}
+
+// key identifies equal constraints, so templates sharing them share trie nodes.
+func (c *paramCheck) key() string {
+	if c == nil {
+		return ""
+	}
+	enum := make([]string, 0, len(c.enum))
+	for v := range c.enum {
+		enum = append(enum, v)
+	}
+	sort.Strings(enum)
+	pattern := ""
+	if c.pattern != nil {
+		pattern = c.pattern.String()
+	}
+	return strings.Join([]string{c.typ, c.format, strings.Join(enum, "|"), pattern}, "\x00")
+}
+
+func (c *paramCheck) accepts(v string) bool {
+	if c == nil {
+		return true
+	}
+	switch c.typ {
+	case "integer":
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return false
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return false
+		}
+	case "boolean":
+		if v != "true" && v != "false" {
+			return false
+		}
+	}
+	if c.format == "uuid" && !uuidPattern.MatchString(v) {
+		return false
+	}
+	if c.enum != nil && !c.enum[v] {
+		return false
+	}
+	if c.pattern != nil && !c.pattern.MatchString(v) {
+		return false
+	}
+	return true
+}
@@ -6,11 +6,27 @@ package openapi
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
 )
 
+func init() {
+	// kin-openapi only checks byte/date/date-time formats by default; strict mode
+	// should also reject malformed ids and addresses.
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForUUIDOfRFC4122))
+	openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+	openapi3.DefineIPv4Format()
+	openapi3.DefineIPv6Format()
+}
+
 type Validator struct {
 	spec ISpecProvider
 }
@@ -40,3 +56,234 @@ func (v *Validator) IsEmptyBody(r *http.Request) (bool, error) {
 	r.Body = io.NopCloser(bytes.NewReader(b))
 	return len(strings.TrimSpace(string(b))) == 0, nil
 }
+
+// ValidateRequest checks path/query/header/cookie parameters, the content type and
+// the request body against the operation of the matched route.
+// It returns every violation found; an empty result means the request is valid.
+// The request body stays readable for later stages.
+func (v *Validator) ValidateRequest(r *http.Request, route *Route) []ValidationIssue {
+	rt, ok := v.operationRoute(route)
+	if !ok {
+		return nil
+	}
+
+	if err := bufferBody(r); err != nil {
+		return []ValidationIssue{{In: "body", Pointer: "/body", Message: err.Error()}}
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: route.PathParams(r.URL.Path),
+		Route:      rt,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+
+	err := openapi3filter.ValidateRequest(context.Background(), input)
+	if err == nil {
+		return nil
+	}
+
+	var out []ValidationIssue
+	collectIssues(err, "", "", &out)
+	return out
+}
+
+// ValidateResponse checks a response (status, headers and body) against the
+// responses declared for the route's operation. Undocumented status codes are
+// reported unless the operation declares a default response.
+func (v *Validator) ValidateResponse(route *Route, status int, headers map[string]string, body []byte) []ValidationIssue {
+	rt, ok := v.operationRoute(route)
+	if !ok {
+		return nil
+	}
+
+	h := http.Header{}
+	for k, val := range headers {
+		h.Set(k, val)
+	}
+
+	req := &http.Request{Method: route.Method, URL: &url.URL{Path: route.Swagger}, Header: http.Header{}}
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request: req,
+			Route:   rt,
+		},
+		Status: status,
+		Header: h,
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+			// bodies such as XML or PDF cannot be decoded; status and headers are still checked
+			ExcludeResponseBody: !canDecodeBody(h.Get("Content-Type")),
+		},
+	}
+	input.SetBodyBytes(body)
+
+	err := openapi3filter.ValidateResponse(context.Background(), input)
+	if err == nil {
+		return nil
+	}
+
+	var out []ValidationIssue
+	collectIssues(err, "", "", &out)
+	return out
+}
+
+func canDecodeBody(ct string) bool {
+	if ct == "" {
+		return true
+	}
+	base, _, _ := strings.Cut(ct, ";")
+	base = strings.ToLower(strings.TrimSpace(base))
+	return openapi3filter.RegisteredBodyDecoder(base) != nil || isJSONMediaType(base)
+}
+
+func (v *Validator) operationRoute(route *Route) (*routers.Route, bool) {
+	if route == nil {
+		return nil, false
+	}
+
+	spec := v.spec.GetSpec()
+	if spec == nil || spec.Doc3 == nil || spec.Doc3.Paths == nil {
+		return nil, false
+	}
+
+	item := spec.Doc3.Paths.Find(route.Swagger)
+	op := v.spec.FindOperation(route.Swagger, route.Method)
+	if item == nil || op == nil {
+		return nil, false
+	}
+
+	return &routers.Route{
+		Spec:      spec.Doc3,
+		Path:      route.Swagger,
+		PathItem:  item,
+		Method:    route.Method,
+		Operation: op,
+	}, true
+}
+
+// bufferBody replaces the request body with an in-memory copy that can be re-read.
+func bufferBody(r *http.Request) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	_ = r.Body.Close()
+
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	return nil
+}
+
+func collectIssues(err error, in, name string, out *[]ValidationIssue) {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, inner := range e {
+			collectIssues(inner, in, name, out)
+		}
+		return
+
+	case *openapi3filter.RequestError:
+		in, name = "request", ""
+		switch {
+		case e.Parameter != nil:
+			in, name = e.Parameter.In, e.Parameter.Name
+		case e.RequestBody != nil:
+			in = "body"
+		}
+
+		if hasSchemaError(e.Err) {
+			collectIssues(e.Err, in, name, out)
+			return
+		}
+
+		*out = append(*out, ValidationIssue{
+			In:      in,
+			Name:    name,
+			Pointer: issuePointer(in, name, nil),
+			Message: e.Error(),
+		})
+		return
+
+	case *openapi3filter.ResponseError:
+		if strings.HasPrefix(e.Reason, "response body") && hasSchemaError(e.Err) {
+			collectIssues(e.Err, "body", "", out)
+			return
+		}
+
+		*out = append(*out, ValidationIssue{
+			In:      "response",
+			Pointer: issuePointer("response", "", nil),
+			Message: e.Error(),
+		})
+		return
+
+	case *openapi3filter.SecurityRequirementsError:
+		// the emulator does not enforce authentication
+		return
+	}
+
+	if in == "" {
+		in = "request"
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		*out = append(*out, ValidationIssue{
+			In:      in,
+			Name:    name,
+			Pointer: issuePointer(in, name, schemaErr.JSONPointer()),
+			Message: schemaErr.Reason,
+		})
+		return
+	}
+
+	*out = append(*out, ValidationIssue{
+		In:      in,
+		Name:    name,
+		Pointer: issuePointer(in, name, nil),
+		Message: err.Error(),
+	})
+}
+
+func hasSchemaError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if multi, ok := err.(openapi3.MultiError); ok {
+		for _, inner := range multi {
+			if hasSchemaError(inner) {
+				return true
+			}
+		}
+		return false
+	}
+	var schemaErr *openapi3.SchemaError
+	return errors.As(err, &schemaErr)
+}
+
+func issuePointer(in, name string, rest []string) string {
+	parts := []string{in}
+	if name != "" {
+		parts = append(parts, name)
+	}
+	parts = append(parts, rest...)
+
+	var b strings.Builder
+	for _, p := range parts {
+		p = strings.ReplaceAll(p, "~", "~0")
+		p = strings.ReplaceAll(p, "/", "~1")
+		b.WriteString("/")
+		b.WriteString(p)
+	}
+	return b.String()
+}
@@ -9,9 +9,14 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -22,8 +27,19 @@ type MockSpecProvider struct {
 	mock.Mock
 }
 
-func (m *MockSpecProvider) TryGetExampleBody(swaggerPath, method string) ([]byte, bool) {
+func (m *MockSpecProvider) TryGetExampleResponse(swaggerPath, method string, params map[string]string, pref Preference) (*ExampleResponse, bool) {
+	args := m.Called(swaggerPath, method)
+	resp, _ := args.Get(0).(*ExampleResponse)
+	return resp, args.Bool(1)
+}
+
+func (m *MockSpecProvider) HasExample(swaggerPath, method string) bool {
 	args := m.Called(swaggerPath, method)
+	return args.Bool(0)
+}
+
+func (m *MockSpecProvider) TryGetStatusExampleBody(swaggerPath, method string, status int, params map[string]string) ([]byte, bool) {
+	args := m.Called(swaggerPath, method, status)
 	b, _ := args.Get(0).([]byte)
 	return b, args.Bool(1)
 }
@@ -181,3 +197,226 @@ func TestValidator_IsEmptyBody_ReadError(t *testing.T) {
 	_, err := v.IsEmptyBody(req)
 	require.Error(t, err)
 }
+
+func newStrictValidator(t *testing.T) (IValidator, IRouterProvider) {
+	t.Helper()
+
+	dir := t.TempDir()
+	p := filepath.Join(dir, "oas3.json")
+
+	specJSON := `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans/{id}":{
+		  "parameters":[
+			{"name":"id","in":"path","required":true,"schema":{"type":"string","format":"uuid"}}
+		  ],
+		  "post":{
+			"parameters":[
+			  {"name":"verbose","in":"query","schema":{"type":"boolean"}},
+			  {"name":"X-API-KEY","in":"header","required":true,"schema":{"type":"string"}}
+			],
+			"requestBody":{
+			  "required":true,
+			  "content":{
+				"application/json":{
+				  "schema":{
+					"type":"object",
+					"required":["action"],
+					"properties":{
+					  "action":{"type":"string","enum":["start","stop"]},
+					  "target":{
+						"type":"object",
+						"properties":{"hosts":{"type":"array","items":{"type":"string"}}}
+					  }
+					}
+				  }
+				}
+			  }
+			},
+			"responses":{"204":{"description":"ok"}}
+		  }
+		}
+	  }
+	}`
+
+	require.NoError(t, os.WriteFile(p, []byte(specJSON), 0o600))
+
+	provider, err := NewSpecProvider(p, logrus.New())
+	require.NoError(t, err)
+
+	return NewValidator(provider), NewRouterProvider(provider.GetSpec())
+}
+
+func TestValidator_ValidateRequest_ValidRequest(t *testing.T) {
+	v, rp := newStrictValidator(t)
+
+	body := `{"action":"start","target":{"hosts":["127.0.0.1"]}}`
+	req := httptest.NewRequest(http.MethodPost,
+		"/scans/6c0e0a8e-9d7b-4c5e-8f43-2a4f0b1e6a11?verbose=true", strings.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("X-API-KEY", "secret")
+
+	rt := rp.FindRoute(http.MethodPost, req.URL.Path)
+	require.NotNil(t, rt)
+
+	require.Empty(t, v.ValidateRequest(req, rt))
+
+	b, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, body, string(b))
+}
+
+func TestValidator_ValidateRequest_ReportsEveryViolation(t *testing.T) {
+	v, rp := newStrictValidator(t)
+
+	body := `{"action":"pause","target":{"hosts":[1]}}`
+	req := httptest.NewRequest(http.MethodPost, "/scans/not-a-uuid?verbose=maybe", strings.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+
+	rt := rp.FindRoute(http.MethodPost, req.URL.Path)
+	require.NotNil(t, rt)
+
+	issues := v.ValidateRequest(req, rt)
+
+	pointers := map[string]ValidationIssue{}
+	for _, is := range issues {
+		pointers[is.Pointer] = is
+	}
+
+	require.Contains(t, pointers, "/path/id")
+	require.Contains(t, pointers, "/query/verbose")
+	require.Contains(t, pointers, "/header/X-API-KEY")
+	require.Contains(t, pointers, "/body/action")
+	require.Contains(t, pointers, "/body/target/hosts/0")
+	require.Equal(t, "body", pointers["/body/action"].In)
+	require.Equal(t, "verbose", pointers["/query/verbose"].Name)
+}
+
+func TestValidator_ValidateRequest_MissingBodyAndWrongContentType(t *testing.T) {
+	v, rp := newStrictValidator(t)
+
+	rt := rp.FindRoute(http.MethodPost, "/scans/6c0e0a8e-9d7b-4c5e-8f43-2a4f0b1e6a11")
+	require.NotNil(t, rt)
+
+	req := httptest.NewRequest(http.MethodPost, "/scans/6c0e0a8e-9d7b-4c5e-8f43-2a4f0b1e6a11", nil)
+	req.Header.Set("X-API-KEY", "secret")
+
+	issues := v.ValidateRequest(req, rt)
+	require.Len(t, issues, 1)
+	require.Equal(t, "/body", issues[0].Pointer)
+
+	req = httptest.NewRequest(http.MethodPost, "/scans/6c0e0a8e-9d7b-4c5e-8f43-2a4f0b1e6a11",
+		strings.NewReader("action=start"))
+	req.Header.Set("content-type", "text/plain")
+	req.Header.Set("X-API-KEY", "secret")
+
+	issues = v.ValidateRequest(req, rt)
+	require.Len(t, issues, 1)
+	require.Equal(t, "body", issues[0].In)
+	require.Contains(t, issues[0].Message, "Content-Type")
+}
+
+func TestValidator_ValidateRequest_NilGuards(t *testing.T) {
+	m := new(MockSpecProvider)
+	v := NewValidator(m)
+
+	require.Nil(t, v.ValidateRequest(httptest.NewRequest(http.MethodGet, "/x", nil), nil))
+
+	m.On("GetSpec").Return((*Spec)(nil)).Once()
+	require.Nil(t, v.ValidateRequest(httptest.NewRequest(http.MethodGet, "/x", nil), &Route{Method: "GET", Swagger: "/x"}))
+	m.AssertExpectations(t)
+}
+
+func TestIssuePointer_EscapesSegments(t *testing.T) {
+	require.Equal(t, "/body/a~1b/c~0d", issuePointer("body", "", []string{"a/b", "c~d"}))
+	require.Equal(t, "/query/limit", issuePointer("query", "limit", nil))
+}
+
+func newResponseValidator(t *testing.T) (IValidator, IRouterProvider) {
+	t.Helper()
+
+	dir := t.TempDir()
+	p := filepath.Join(dir, "oas3.json")
+
+	specJSON := `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans/{id}/status":{
+		  "get":{
+			"responses":{
+			  "200":{
+				"description":"ok",
+				"content":{
+				  "application/json":{
+					"schema":{
+					  "type":"object",
+					  "required":["status"],
+					  "properties":{
+						"status":{"type":"string","enum":["requested","running","succeeded"]},
+						"host_info":{"type":"object","properties":{"all":{"type":"integer"}}}
+					  }
+					}
+				  }
+				}
+			  },
+			  "404":{"description":"not found"}
+			}
+		  }
+		}
+	  }
+	}`
+
+	require.NoError(t, os.WriteFile(p, []byte(specJSON), 0o600))
+
+	provider, err := NewSpecProvider(p, logrus.New())
+	require.NoError(t, err)
+
+	return NewValidator(provider), NewRouterProvider(provider.GetSpec())
+}
+
+func TestValidator_ValidateResponse_Valid(t *testing.T) {
+	v, rp := newResponseValidator(t)
+	rt := rp.FindRoute(http.MethodGet, "/scans/1/status")
+	require.NotNil(t, rt)
+
+	issues := v.ValidateResponse(rt, 200, map[string]string{"content-type": "application/json"},
+		[]byte(`{"status":"running","host_info":{"all":3}}`))
+	require.Empty(t, issues)
+
+	require.Empty(t, v.ValidateResponse(rt, 404, map[string]string{"content-type": "application/json"}, []byte(`{}`)))
+}
+
+func TestValidator_ValidateResponse_BodyDrift(t *testing.T) {
+	v, rp := newResponseValidator(t)
+	rt := rp.FindRoute(http.MethodGet, "/scans/1/status")
+	require.NotNil(t, rt)
+
+	issues := v.ValidateResponse(rt, 200, map[string]string{"Content-Type": "application/json"},
+		[]byte(`{"status":"paused","host_info":{"all":"3"}}`))
+
+	pointers := map[string]bool{}
+	for _, is := range issues {
+		require.Equal(t, "body", is.In)
+		pointers[is.Pointer] = true
+	}
+	require.True(t, pointers["/body/status"], "issues: %+v", issues)
+	require.True(t, pointers["/body/host_info/all"], "issues: %+v", issues)
+}
+
+func TestValidator_ValidateResponse_UndocumentedStatusAndContentType(t *testing.T) {
+	v, rp := newResponseValidator(t)
+	rt := rp.FindRoute(http.MethodGet, "/scans/1/status")
+	require.NotNil(t, rt)
+
+	issues := v.ValidateResponse(rt, 500, map[string]string{"content-type": "application/json"}, []byte(`{}`))
+	require.Len(t, issues, 1)
+	require.Equal(t, "response", issues[0].In)
+	require.Contains(t, issues[0].Message, "status is not supported")
+
+	issues = v.ValidateResponse(rt, 200, map[string]string{"content-type": "text/plain"}, []byte(`running`))
+	require.Len(t, issues, 1)
+	require.Equal(t, "/response", issues[0].Pointer)
+}
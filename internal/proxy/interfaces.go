@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package proxy
+
+import "net/http"
+
+type IProxy interface {
+	Forward(r *http.Request, body []byte) (*Response, error)
+}
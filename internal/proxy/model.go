@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package proxy
+
+import (
+	"net/http"
+	"time"
+)
+
+type Config struct {
+	// URL is the upstream base URL; its path is prefixed to every forwarded request path.
+	URL     string
+	Timeout time.Duration
+	// Headers are set on every forwarded request; an empty value removes the header.
+	Headers map[string]string
+}
+
+// Response is an upstream response read in full.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
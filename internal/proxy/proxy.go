@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// hopHeaders are connection-level headers that must not be forwarded.
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// Proxy forwards requests to an upstream service.
+type Proxy struct {
+	base    *url.URL
+	headers map[string]string
+	client  *http.Client
+	log     *logrus.Logger
+}
+
+func NewProxy(cfg Config, log *logrus.Logger) (IProxy, error) {
+	base, err := url.Parse(strings.TrimSpace(cfg.URL))
+	if err != nil {
+		return nil, fmt.Errorf("upstream url: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" || base.Host == "" {
+		return nil, fmt.Errorf("upstream url %q must be an absolute http(s) URL", cfg.URL)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	return &Proxy{
+		base:    base,
+		headers: cfg.Headers,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// redirects go back to the client unchanged
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		log: log,
+	}, nil
+}
+
+// Forward sends r with the given body to the upstream and reads the whole response.
+func (p *Proxy) Forward(r *http.Request, body []byte) (*Response, error) {
+	target := *p.base
+	target.Path = strings.TrimSuffix(p.base.Path, "/") + r.URL.Path
+	target.RawPath = ""
+	target.RawQuery = r.URL.RawQuery
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	removeHopHeaders(req.Header)
+	// let the transport negotiate compression so bodies arrive decoded
+	req.Header.Del("Accept-Encoding")
+	for k, v := range p.headers {
+		if v == "" {
+			req.Header.Del(k)
+		} else {
+			req.Header.Set(k, v)
+		}
+	}
+	req.Host = p.base.Host
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("forward %s %s: %w", r.Method, target.String(), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read upstream response: %w", err)
+	}
+
+	header := resp.Header.Clone()
+	removeHopHeaders(header)
+	header.Del("Content-Length")
+
+	p.log.WithFields(logrus.Fields{
+		"method":   r.Method,
+		"upstream": target.String(),
+		"status":   resp.StatusCode,
+		"duration": time.Since(start).String(),
+	}).Debug("forwarded request")
+
+	return &Response{Status: resp.StatusCode, Header: header, Body: b}, nil
+}
+
+func removeHopHeaders(h http.Header) {
+	for _, k := range strings.Split(h.Get("Connection"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			h.Del(k)
+		}
+	}
+	for _, k := range hopHeaders {
+		h.Del(k)
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProxy_RejectsBadURL(t *testing.T) {
+	for _, u := range []string{"", "openvasd:3000", "ftp://host", "http://"} {
+		_, err := NewProxy(Config{URL: u}, logrus.New())
+		require.Error(t, err, u)
+	}
+}
+
+func TestProxy_Forward(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Upstream", "yes")
+		w.Header().Set("Connection", "close")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer upstream.Close()
+
+	p, err := NewProxy(Config{
+		URL:     upstream.URL + "/api/",
+		Headers: map[string]string{"X-Api-Key": "upstream-key", "Cookie": ""},
+	}, logrus.New())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://emulator/scans?x=1", strings.NewReader("ignored"))
+	req.Header.Set("X-Api-Key", "k")
+	req.Header.Set("X-Client", "c")
+	req.Header.Set("Cookie", "session=1")
+	req.Header.Set("Keep-Alive", "timeout=5")
+
+	resp, err := p.Forward(req, []byte(`{"target":"a"}`))
+	require.NoError(t, err)
+
+	require.Equal(t, "/api/scans", got.URL.Path)
+	require.Equal(t, "x=1", got.URL.RawQuery)
+	require.Equal(t, "upstream-key", got.Header.Get("X-Api-Key"))
+	require.Equal(t, "c", got.Header.Get("X-Client"))
+	require.Empty(t, got.Header.Get("Cookie"))
+	require.Empty(t, got.Header.Get("Keep-Alive"))
+	require.Equal(t, `{"target":"a"}`, gotBody)
+
+	require.Equal(t, http.StatusCreated, resp.Status)
+	require.Equal(t, "yes", resp.Header.Get("X-Upstream"))
+	require.Empty(t, resp.Header.Get("Connection"))
+	require.Equal(t, `{"id":"1"}`, string(resp.Body))
+}
+
+func TestProxy_Forward_DoesNotFollowRedirects(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer upstream.Close()
+
+	p, err := NewProxy(Config{URL: upstream.URL}, logrus.New())
+	require.NoError(t, err)
+
+	resp, err := p.Forward(httptest.NewRequest(http.MethodGet, "/x", nil), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusFound, resp.Status)
+	require.Equal(t, "/elsewhere", resp.Header.Get("Location"))
+}
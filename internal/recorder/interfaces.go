@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package recorder
+
+import "github.com/greenbone/gvm-openapi-emulator/internal/proxy"
+
+type IRecorder interface {
+	Record(method, swaggerTpl, actualPath string, resp *proxy.Response) error
+}
@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package recorder
+
+import "github.com/greenbone/gvm-openapi-emulator/internal/samples"
+
+type Config struct {
+	SamplesDir       string
+	ScenarioFilename string
+}
+
+// folder is what was recorded for one endpoint folder (swagger path template).
+type folder struct {
+	methods map[string]*methodCalls
+	// written are the files this recorder currently owns in the folder
+	written map[string]bool
+}
+
+// methodCalls holds the captured responses of one method, per concrete path.
+type methodCalls struct {
+	byPath map[string][]samples.Envelope
+	order  []string
+	last   samples.Envelope
+}
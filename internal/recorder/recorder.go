@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/proxy"
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+)
+
+// skippedHeaders describe a single transfer and are not worth keeping in a sample.
+var skippedHeaders = map[string]bool{
+	"content-length": true,
+	"date":           true,
+}
+
+// Recorder writes upstream responses as folder-layout samples. A route called once
+// gets a plain <METHOD>.json; a route called repeatedly for the same key becomes
+// numbered state files and a generated step scenario.
+type Recorder struct {
+	mu      sync.Mutex
+	cfg     Config
+	folders map[string]*folder
+	log     *logrus.Logger
+}
+
+func NewRecorder(cfg Config, log *logrus.Logger) IRecorder {
+	if strings.TrimSpace(cfg.ScenarioFilename) == "" {
+		cfg.ScenarioFilename = "scenario.json"
+	}
+	return &Recorder{cfg: cfg, folders: map[string]*folder{}, log: log}
+}
+
+func (rec *Recorder) Record(method, swaggerTpl, actualPath string, resp *proxy.Response) error {
+	method = strings.ToUpper(method)
+	env := envelopeOf(resp)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	f, ok := rec.folders[swaggerTpl]
+	if !ok {
+		f = &folder{methods: map[string]*methodCalls{}, written: map[string]bool{}}
+		rec.folders[swaggerTpl] = f
+	}
+	mc, ok := f.methods[method]
+	if !ok {
+		mc = &methodCalls{byPath: map[string][]samples.Envelope{}}
+		f.methods[method] = mc
+	}
+	if _, ok := mc.byPath[actualPath]; !ok {
+		mc.order = append(mc.order, actualPath)
+	}
+	mc.byPath[actualPath] = append(mc.byPath[actualPath], env)
+	mc.last = env
+
+	dir := filepath.Join(rec.cfg.SamplesDir, filepath.FromSlash(strings.TrimPrefix(swaggerTpl, "/")))
+	if err := rec.writeFolder(dir, swaggerTpl, f); err != nil {
+		return fmt.Errorf("record %s %s: %w", method, swaggerTpl, err)
+	}
+	return nil
+}
+
+// writeFolder rewrites the recorded files of a folder from memory and removes
+// files this recorder wrote before but no longer produces.
+func (rec *Recorder) writeFolder(dir, swaggerTpl string, f *folder) error {
+	files := map[string]any{}
+
+	keyParam := lastPathParam(swaggerTpl)
+	method, seq := longestSequence(f)
+	if len(f.methods) == 1 && keyParam != "" && len(seq) > 1 {
+		// a scenario answers every method of the folder, so it is only generated
+		// while a single method has been recorded there
+		sc := samples.Scenario{Version: 1, Mode: "step"}
+		sc.Key.PathParam = keyParam
+		sc.Behavior = samples.Behavior{
+			AdvanceOn:  []samples.MatchRule{{Method: method}},
+			RepeatLast: true,
+		}
+		for i, env := range seq {
+			state := strconv.Itoa(i + 1)
+			name := fmt.Sprintf("%s.%s.json", method, state)
+			files[name] = env
+			sc.Sequence = append(sc.Sequence, samples.ScenarioEntry{State: state, File: name})
+		}
+		files[rec.cfg.ScenarioFilename] = sc
+	} else {
+		for m, mc := range f.methods {
+			files[m+".json"] = mc.last
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	for name, v := range files {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), append(b, '\n'), 0o600); err != nil {
+			return err
+		}
+		f.written[name] = true
+	}
+	for name := range f.written {
+		if _, ok := files[name]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		delete(f.written, name)
+	}
+
+	rec.log.WithFields(logrus.Fields{
+		"dir":   dir,
+		"files": len(files),
+	}).Debug("recorded samples")
+	return nil
+}
+
+// longestSequence returns the method and the longest per-path call sequence of a folder.
+func longestSequence(f *folder) (string, []samples.Envelope) {
+	methods := make([]string, 0, len(f.methods))
+	for m := range f.methods {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+
+	var bestMethod string
+	var best []samples.Envelope
+	for _, m := range methods {
+		mc := f.methods[m]
+		for _, p := range mc.order {
+			if seq := mc.byPath[p]; len(seq) > len(best) {
+				bestMethod, best = m, seq
+			}
+		}
+	}
+	return bestMethod, best
+}
+
+func lastPathParam(swaggerTpl string) string {
+	parts := strings.Split(strings.Trim(swaggerTpl, "/"), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		p := parts[i]
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			return strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}")
+		}
+	}
+	return ""
+}
+
+func envelopeOf(resp *proxy.Response) samples.Envelope {
+	env := samples.Envelope{Status: resp.Status, Headers: map[string]string{}}
+	for k, v := range resp.Header {
+		k = strings.ToLower(k)
+		if skippedHeaders[k] || len(v) == 0 {
+			continue
+		}
+		env.Headers[k] = strings.Join(v, ", ")
+	}
+
+	if len(resp.Body) == 0 {
+		return env
+	}
+	var body any
+	dec := json.NewDecoder(bytes.NewReader(resp.Body))
+	dec.UseNumber()
+	switch err := dec.Decode(&body); {
+	case err == nil && !dec.More():
+		env.Body = body
+	case utf8.Valid(resp.Body):
+		// text bodies are kept as a string and served as they are
+		env.Body = string(resp.Body)
+	default:
+		env.Body = base64.StdEncoding.EncodeToString(resp.Body)
+		env.BodyEncoding = "base64"
+	}
+	return env
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package recorder
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/proxy"
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+)
+
+func jsonResponse(status int, body string) *proxy.Response {
+	return &proxy.Response{
+		Status: status,
+		Header: http.Header{"Content-Type": {"application/json"}, "Date": {"Mon, 02 Jan 2026 00:00:00 GMT"}},
+		Body:   []byte(body),
+	}
+}
+
+func readFile(t *testing.T, p string) string {
+	t.Helper()
+	b, err := os.ReadFile(p)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestRecorder_SingleCall_WritesPlainEnvelope(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(Config{SamplesDir: dir}, logrus.New())
+
+	require.NoError(t, rec.Record("post", "/scans", "/scans", jsonResponse(201, `{"id":"abc","n":12345678901234567}`)))
+
+	b := readFile(t, filepath.Join(dir, "scans", "POST.json"))
+	require.JSONEq(t, `{
+	  "status": 201,
+	  "headers": {"content-type":"application/json"},
+	  "body": {"id":"abc","n":12345678901234567}
+	}`, b)
+	require.NoError(t, samples.CheckSample([]byte(b)))
+}
+
+func TestRecorder_RepeatedCalls_BecomeStepScenario(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(Config{SamplesDir: dir, ScenarioFilename: "scenario.json"}, logrus.New())
+	folder := filepath.Join(dir, "scans", "{id}", "status")
+
+	require.NoError(t, rec.Record("GET", "/scans/{id}/status", "/scans/a/status", jsonResponse(200, `{"status":"requested"}`)))
+	require.FileExists(t, filepath.Join(folder, "GET.json"))
+
+	require.NoError(t, rec.Record("GET", "/scans/{id}/status", "/scans/a/status", jsonResponse(200, `{"status":"running"}`)))
+	require.NoError(t, rec.Record("GET", "/scans/{id}/status", "/scans/b/status", jsonResponse(200, `{"status":"requested"}`)))
+	require.NoError(t, rec.Record("GET", "/scans/{id}/status", "/scans/a/status", jsonResponse(200, `{"status":"succeeded"}`)))
+
+	require.NoFileExists(t, filepath.Join(folder, "GET.json"), "plain sample is replaced by the scenario")
+	require.JSONEq(t, `{"status":200,"headers":{"content-type":"application/json"},"body":{"status":"succeeded"}}`,
+		readFile(t, filepath.Join(folder, "GET.3.json")))
+
+	sc, err := samples.LoadScenario(filepath.Join(folder, "scenario.json"))
+	require.NoError(t, err)
+	require.Equal(t, "step", sc.Mode)
+	require.Equal(t, "id", sc.Key.PathParam)
+	require.Equal(t, []samples.ScenarioEntry{
+		{State: "1", File: "GET.1.json"},
+		{State: "2", File: "GET.2.json"},
+		{State: "3", File: "GET.3.json"},
+	}, sc.Sequence)
+	require.Equal(t, []samples.MatchRule{{Method: "GET"}}, sc.Behavior.AdvanceOn)
+	require.True(t, sc.Behavior.RepeatLast)
+}
+
+func TestRecorder_SecondMethod_DropsGeneratedScenario(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(Config{SamplesDir: dir}, logrus.New())
+	folder := filepath.Join(dir, "scans", "{id}")
+
+	require.NoError(t, rec.Record("GET", "/scans/{id}", "/scans/a", jsonResponse(200, `{"v":1}`)))
+	require.NoError(t, rec.Record("GET", "/scans/{id}", "/scans/a", jsonResponse(200, `{"v":2}`)))
+	require.FileExists(t, filepath.Join(folder, "scenario.json"))
+
+	require.NoError(t, rec.Record("DELETE", "/scans/{id}", "/scans/a", &proxy.Response{Status: 204, Header: http.Header{}}))
+
+	entries, err := os.ReadDir(folder)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.ElementsMatch(t, []string{"DELETE.json", "GET.json"}, names)
+	require.JSONEq(t, `{"status":200,"headers":{"content-type":"application/json"},"body":{"v":2}}`,
+		readFile(t, filepath.Join(folder, "GET.json")))
+}
+
+func TestRecorder_NoPathParam_KeepsLatestResponse(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(Config{SamplesDir: dir}, logrus.New())
+
+	require.NoError(t, rec.Record("GET", "/vts", "/vts", jsonResponse(200, `["1"]`)))
+	require.NoError(t, rec.Record("GET", "/vts", "/vts", &proxy.Response{Status: 200, Header: http.Header{}, Body: []byte("plain text")}))
+
+	require.NoFileExists(t, filepath.Join(dir, "vts", "scenario.json"))
+	require.JSONEq(t, `{"status":200,"headers":{},"body":"plain text"}`, readFile(t, filepath.Join(dir, "vts", "GET.json")))
+}
+
+func TestRecorder_NonJSONBodies_ReplayAsRecorded(t *testing.T) {
+	dir := t.TempDir()
+	rec := NewRecorder(Config{SamplesDir: dir}, logrus.New())
+
+	xml := &proxy.Response{Status: 200, Header: http.Header{"Content-Type": {"application/xml"}}, Body: []byte(`<report id="1"/>`)}
+	pdf := &proxy.Response{Status: 200, Header: http.Header{"Content-Type": {"application/pdf"}}, Body: []byte{'%', 'P', 'D', 'F', 0xff, 0x00}}
+	require.NoError(t, rec.Record("GET", "/reports/{id}", "/reports/1", xml))
+	require.NoError(t, rec.Record("GET", "/reports/{id}/pdf", "/reports/1/pdf", pdf))
+
+	p := samples.NewSampleProvider(samples.ProviderConfig{BaseDir: dir, Layout: config.LayoutFolders}, logrus.New())
+
+	resp, err := p.ResolveAndLoad("GET", "/reports/{id}", "/reports/1", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, `<report id="1"/>`, string(resp.Body))
+
+	resp, err = p.ResolveAndLoad("GET", "/reports/{id}/pdf", "/reports/1/pdf", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, pdf.Body, resp.Body)
+	require.Equal(t, "application/pdf", resp.Headers["content-type"])
+}
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package resources
+
+type IStore interface {
+	Create(collection string, res map[string]any) (id string, stored map[string]any, err error)
+	Put(collection, id string, res map[string]any) (stored map[string]any, created bool)
+	Get(collection, id string) (map[string]any, bool)
+	List(collection string) []map[string]any
+	Delete(collection, id string) bool
+	Reset() int
+	Counts() map[string]int
+}
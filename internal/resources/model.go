@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package resources
+
+// collection holds the resources of one concrete collection path in insertion order.
+type collection struct {
+	order []string
+	items map[string]map[string]any
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+)
+
+// ErrInvalidID reports a resource id that cannot be part of a path.
+var ErrInvalidID = errors.New("resource id must not contain \"/\"")
+
+// Store keeps resources in memory, keyed by their concrete collection path
+// (e.g. /scans or /scans/abc/results) and id.
+type Store struct {
+	mu          sync.Mutex
+	idField     string
+	collections map[string]*collection
+}
+
+func NewStore(idField string) IStore {
+	if strings.TrimSpace(idField) == "" {
+		idField = "id"
+	}
+	return &Store{idField: idField, collections: map[string]*collection{}}
+}
+
+// Create stores res under the id it carries in the id field, or under a
+// generated one that is written back into the resource. An id containing "/"
+// could never be addressed by its item path and is rejected.
+func (s *Store) Create(coll string, res map[string]any) (string, map[string]any, error) {
+	id := idOf(res[s.idField])
+	if strings.Contains(id, "/") {
+		return "", nil, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	if id == "" {
+		id = utils.NewUUID()
+		res[s.idField] = id
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(coll, id, res)
+	return id, clone(res), nil
+}
+
+// Put creates or replaces the resource with the given id.
+func (s *Store) Put(coll, id string, res map[string]any) (map[string]any, bool) {
+	if _, ok := res[s.idField]; !ok {
+		res[s.idField] = id
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created := s.put(coll, id, res)
+	return clone(res), created
+}
+
+func (s *Store) Get(coll, id string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[coll]
+	if !ok {
+		return nil, false
+	}
+	res, ok := c.items[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(res), true
+}
+
+func (s *Store) List(coll string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := []map[string]any{}
+	c, ok := s.collections[coll]
+	if !ok {
+		return out
+	}
+	for _, id := range c.order {
+		out = append(out, clone(c.items[id]))
+	}
+	return out
+}
+
+// Delete removes a resource together with every collection nested below it.
+func (s *Store) Delete(coll, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[coll]
+	if !ok {
+		return false
+	}
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+
+	delete(c.items, id)
+	for i, v := range c.order {
+		if v == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+
+	prefix := strings.TrimSuffix(coll, "/") + "/" + id + "/"
+	for k := range s.collections {
+		if strings.HasPrefix(k, prefix) {
+			delete(s.collections, k)
+		}
+	}
+	return true
+}
+
+// Reset drops every stored resource and returns how many there were.
+func (s *Store) Reset() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, c := range s.collections {
+		n += len(c.items)
+	}
+	s.collections = map[string]*collection{}
+	return n
+}
+
+// Counts reports the number of resources per non-empty collection path.
+func (s *Store) Counts() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := map[string]int{}
+	for k, c := range s.collections {
+		if len(c.items) > 0 {
+			out[k] = len(c.items)
+		}
+	}
+	return out
+}
+
+// put stores a resource and reports whether it is new. Callers hold s.mu.
+func (s *Store) put(coll, id string, res map[string]any) bool {
+	c, ok := s.collections[coll]
+	if !ok {
+		c = &collection{items: map[string]map[string]any{}}
+		s.collections[coll] = c
+	}
+
+	_, exists := c.items[id]
+	if !exists {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = clone(res)
+	return !exists
+}
+
+// idOf turns a body-supplied id (string or JSON number) into its path form.
+func idOf(v any) string {
+	switch x := v.(type) {
+	case string:
+		return strings.TrimSpace(x)
+	case json.Number:
+		return x.String()
+	case float64, int, int64:
+		return fmt.Sprint(x)
+	default:
+		return ""
+	}
+}
+
+// clone deep-copies a JSON-shaped resource so callers never share stored state.
+func clone(res map[string]any) map[string]any {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return res
+	}
+	var out map[string]any
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&out); err != nil {
+		return res
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package resources
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_CreateGeneratesOrKeepsID(t *testing.T) {
+	s := NewStore("")
+
+	id, stored, err := s.Create("/scans", map[string]any{"name": "a"})
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+	require.Equal(t, id, stored["id"])
+
+	id, _, _ = s.Create("/scans", map[string]any{"id": "fixed", "name": "b"})
+	require.Equal(t, "fixed", id)
+
+	id, _, _ = s.Create("/scans", map[string]any{"id": json.Number("42")})
+	require.Equal(t, "42", id)
+
+	got, ok := s.Get("/scans", "fixed")
+	require.True(t, ok)
+	require.Equal(t, "b", got["name"])
+
+	list := s.List("/scans")
+	require.Len(t, list, 3)
+	require.Equal(t, "a", list[0]["name"], "list keeps insertion order")
+}
+
+func TestStore_CreateRejectsSlashInID(t *testing.T) {
+	s := NewStore("")
+
+	_, _, err := s.Create("/scans", map[string]any{"id": "a/b"})
+	require.ErrorIs(t, err, ErrInvalidID)
+	require.Empty(t, s.List("/scans"))
+}
+
+func TestStore_CustomIDField(t *testing.T) {
+	s := NewStore("uuid")
+
+	id, stored, err := s.Create("/targets", map[string]any{"uuid": "t1"})
+	require.NoError(t, err)
+	require.Equal(t, "t1", id)
+	require.NotContains(t, stored, "id")
+}
+
+func TestStore_PutReplacesAndReportsCreated(t *testing.T) {
+	s := NewStore("id")
+
+	stored, created := s.Put("/scans", "abc", map[string]any{"name": "a"})
+	require.True(t, created)
+	require.Equal(t, "abc", stored["id"])
+
+	_, created = s.Put("/scans", "abc", map[string]any{"name": "b"})
+	require.False(t, created)
+
+	got, _ := s.Get("/scans", "abc")
+	require.Equal(t, "b", got["name"])
+	require.Len(t, s.List("/scans"), 1)
+}
+
+func TestStore_ReturnedResourcesAreCopies(t *testing.T) {
+	s := NewStore("id")
+	s.Put("/scans", "abc", map[string]any{"tags": []any{"x"}})
+
+	got, _ := s.Get("/scans", "abc")
+	got["tags"] = "mutated"
+
+	again, _ := s.Get("/scans", "abc")
+	require.Equal(t, []any{"x"}, again["tags"])
+}
+
+func TestStore_DeleteDropsNestedCollections(t *testing.T) {
+	s := NewStore("id")
+	s.Put("/scans", "abc", map[string]any{})
+	s.Put("/scans", "def", map[string]any{})
+	s.Put("/scans/abc/results", "r1", map[string]any{})
+	s.Put("/scans/def/results", "r1", map[string]any{})
+
+	require.True(t, s.Delete("/scans", "abc"))
+	require.False(t, s.Delete("/scans", "abc"))
+	require.False(t, s.Delete("/nothing", "abc"))
+
+	require.Empty(t, s.List("/scans/abc/results"))
+	require.Equal(t, map[string]int{"/scans": 1, "/scans/def/results": 1}, s.Counts())
+
+	require.Equal(t, 2, s.Reset())
+	require.Empty(t, s.Counts())
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"sync"
+	"time"
+)
+
+// RealClock reports the wall clock.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+// VirtualClock is a clock that only moves as fast as its source while running,
+// and not at all while frozen. Advance and Set move it independently of the source.
+type VirtualClock struct {
+	mu     sync.Mutex
+	source IClock
+	base   time.Time // virtual time at anchor
+	anchor time.Time // source time when base was taken
+	frozen bool
+}
+
+// NewVirtualClock starts a virtual clock at the source's current time.
+// A nil source means the wall clock.
+func NewVirtualClock(source IClock, frozen bool) IVirtualClock {
+	if source == nil {
+		source = RealClock{}
+	}
+	now := source.Now()
+	return &VirtualClock{source: source, base: now, anchor: now, frozen: frozen}
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now()
+}
+
+func (c *VirtualClock) Frozen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.frozen
+}
+
+func (c *VirtualClock) Freeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebase(c.now())
+	c.frozen = true
+}
+
+func (c *VirtualClock) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebase(c.now())
+	c.frozen = false
+}
+
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebase(c.now().Add(d))
+}
+
+func (c *VirtualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebase(t)
+}
+
+// now is the current virtual time. Callers hold c.mu.
+func (c *VirtualClock) now() time.Time {
+	if c.frozen {
+		return c.base
+	}
+	return c.base.Add(c.source.Now().Sub(c.anchor))
+}
+
+// rebase pins the virtual time to t at the source's current time. Callers hold c.mu.
+func (c *VirtualClock) rebase(t time.Time) {
+	c.base = t
+	c.anchor = c.source.Now()
+}
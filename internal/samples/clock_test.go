@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func TestVirtualClock_FollowsSourceUntilFrozen(t *testing.T) {
+	src := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewVirtualClock(src, false)
+
+	src.t = src.t.Add(5 * time.Second)
+	require.Equal(t, src.t, c.Now())
+
+	c.Freeze()
+	require.True(t, c.Frozen())
+	frozenAt := c.Now()
+
+	src.t = src.t.Add(time.Hour)
+	require.Equal(t, frozenAt, c.Now())
+
+	c.Advance(90 * time.Second)
+	require.Equal(t, frozenAt.Add(90*time.Second), c.Now())
+
+	c.Resume()
+	src.t = src.t.Add(10 * time.Second)
+	require.Equal(t, frozenAt.Add(100*time.Second), c.Now())
+}
+
+func TestVirtualClock_Set(t *testing.T) {
+	src := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewVirtualClock(src, true)
+
+	at := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.Set(at)
+	require.Equal(t, at, c.Now())
+
+	src.t = src.t.Add(time.Minute)
+	require.Equal(t, at, c.Now(), "frozen clock must not follow its source after Set")
+}
+
+func TestScenarioResolver_VirtualClock_DrivesTimeline(t *testing.T) {
+	clock := NewVirtualClock(nil, true)
+	e := NewScenarioResolverWithClock(clock)
+
+	sc := &Scenario{Version: 1, Mode: "time"}
+	sc.Key.PathParam = "id"
+	sc.Timeline = []TimelineEntry{
+		{AfterSec: 0, State: "requested", File: "a.json"},
+		{AfterSec: 60, State: "running", File: "b.json"},
+		{AfterSec: 600, State: "succeeded", File: "c.json"},
+	}
+	sc.Behavior.RepeatLast = true
+
+	resolve := func(path string) string {
+		t.Helper()
+		_, state, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", path)
+		require.NoError(t, err)
+		return state
+	}
+
+	require.Equal(t, "requested", resolve("/scans/a/status"))
+	require.Equal(t, "requested", resolve("/scans/b/status"))
+
+	clock.Advance(61 * time.Second)
+	require.Equal(t, "running", resolve("/scans/a/status"))
+
+	// a key clock moves one key independently of the others
+	kc, err := e.Clock("/scans/{id}/status", "b")
+	require.NoError(t, err)
+	kc.Advance(10 * time.Minute)
+	require.Equal(t, "succeeded", resolve("/scans/b/status"))
+	require.Equal(t, "running", resolve("/scans/a/status"))
+
+	clocks := e.Clocks()
+	require.Len(t, clocks, 2)
+	require.Empty(t, clocks[0].Key)
+	require.True(t, clocks[0].Frozen)
+	require.Equal(t, "b", clocks[1].Key)
+	require.Equal(t, clocks[0].Now.Add(10*time.Minute), clocks[1].Now)
+
+	// resetting one key drops its clock too
+	require.True(t, e.Reset("/scans/{id}/status", "b"))
+	require.Len(t, e.Clocks(), 1)
+	require.Equal(t, "requested", resolve("/scans/b/status"))
+
+	// a reset drops the key clocks along with the steps
+	_, err = e.Clock("/scans/{id}/status", "b")
+	require.NoError(t, err)
+	require.Equal(t, 2, e.ResetAll())
+	require.Len(t, e.Clocks(), 1)
+	require.Equal(t, "requested", resolve("/scans/b/status"))
+}
+
+func TestScenarioResolver_RealClock_HasNoVirtualClock(t *testing.T) {
+	e := NewScenarioResolver()
+
+	_, err := e.Clock("", "")
+	require.ErrorIs(t, err, ErrClockNotVirtual)
+	require.Empty(t, e.Clocks())
+}
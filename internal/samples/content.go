@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrBodyFile reports an envelope bodyFile that cannot be served.
+var ErrBodyFile = errors.New("invalid body file")
+
+// SampleExtensions are the extensions a folder sample <METHOD>[.<state>].<ext> may
+// have, tried in this order. Only .json files are parsed; the others are served as
+// they are, with the content type of their extension.
+var SampleExtensions = []string{".json", ".xml", ".txt", ".csv", ".html", ".yaml", ".pdf", ".zip", ".bin"}
+
+var extContentTypes = map[string]string{
+	".json": "application/json",
+	".xml":  "application/xml",
+	".txt":  "text/plain; charset=utf-8",
+	".csv":  "text/csv",
+	".html": "text/html; charset=utf-8",
+	".yaml": "application/yaml",
+	".pdf":  "application/pdf",
+	".zip":  "application/zip",
+	".bin":  "application/octet-stream",
+}
+
+// ContentTypeForFile returns the content type a file is served with, by its
+// extension; unknown extensions are application/octet-stream.
+func ContentTypeForFile(name string) string {
+	if ct, ok := extContentTypes[strings.ToLower(filepath.Ext(name))]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// IsSampleExtension reports whether ext, including the dot, is one of SampleExtensions.
+func IsSampleExtension(ext string) bool {
+	_, ok := extContentTypes[strings.ToLower(ext)]
+	return ok
+}
+
+// IsJSONContentType reports whether ct is application/json or a +json type.
+func IsJSONContentType(ct string) bool {
+	base := mediaType(ct)
+	return base == "application/json" || strings.HasSuffix(base, "+json")
+}
+
+// IsTextContentType reports whether a body of type ct is text, so it may be templated.
+func IsTextContentType(ct string) bool {
+	base := mediaType(ct)
+	switch {
+	case strings.HasPrefix(base, "text/"), IsJSONContentType(base),
+		strings.HasSuffix(base, "+xml"), strings.HasSuffix(base, "+yaml"):
+		return true
+	}
+	return base == "application/xml" || base == "application/yaml" || base == "application/x-yaml"
+}
+
+func mediaType(ct string) string {
+	base, _, _ := strings.Cut(ct, ";")
+	return strings.ToLower(strings.TrimSpace(base))
+}
+
+// EnvelopeBodyFile returns the bodyFile of the envelope in a .json sample file, if any.
+func EnvelopeBodyFile(path string) string {
+	if filepath.Ext(path) != ".json" {
+		return ""
+	}
+	b, err := os.ReadFile(path) // #nosec G304 -- sample file below the samples dir
+	if err != nil || !looksLikeEnvelope(b) {
+		return ""
+	}
+	var env Envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return ""
+	}
+	return env.BodyFile
+}
+
+// envelopeContentType is the content type of an envelope without a content-type header.
+func envelopeContentType(env Envelope) string {
+	switch {
+	case env.BodyFile != "":
+		return ContentTypeForFile(env.BodyFile)
+	case env.BodyEncoding != "":
+		return "application/octet-stream"
+	}
+	return "application/json"
+}
+
+// envelopeBody returns the bytes an envelope serves. bodyFile is resolved against
+// dir and must stay below root. A string body is served as is unless the content
+// type is JSON.
+func envelopeBody(env Envelope, ct, dir, root string) ([]byte, error) {
+	switch {
+	case env.BodyFile != "":
+		full := filepath.Join(dir, filepath.FromSlash(env.BodyFile))
+		if root == "" || !within(root, full) {
+			return nil, fmt.Errorf("%w: %s is outside the samples dir", ErrBodyFile, env.BodyFile)
+		}
+		// a symlink below the samples dir must not lead out of it
+		resolved, err := filepath.EvalSymlinks(full)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBodyFile, err)
+		}
+		realRoot, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBodyFile, err)
+		}
+		if !within(realRoot, resolved) {
+			return nil, fmt.Errorf("%w: %s is outside the samples dir", ErrBodyFile, env.BodyFile)
+		}
+		b, err := os.ReadFile(resolved) // #nosec G304 -- checked to be below the samples dir
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBodyFile, err)
+		}
+		return b, nil
+	case env.BodyEncoding == "base64":
+		s, ok := env.Body.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: base64 body must be a string", ErrMalformedEnvelope)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: base64 body: %v", ErrMalformedEnvelope, err)
+		}
+		return b, nil
+	case env.BodyEncoding != "":
+		return nil, fmt.Errorf("%w: unsupported bodyEncoding %q", ErrMalformedEnvelope, env.BodyEncoding)
+	case env.Body == nil:
+		if IsJSONContentType(ct) {
+			return []byte("{}"), nil
+		}
+		return nil, nil
+	}
+
+	if s, ok := env.Body.(string); ok && !IsJSONContentType(ct) {
+		return []byte(s), nil
+	}
+	b, err := json.Marshal(env.Body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal envelope body: %w", err)
+	}
+	return b, nil
+}
+
+// within reports whether path is root or below it.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -4,8 +4,10 @@
 
 package samples
 
+import "time"
+
 type ISampleProvider interface {
-	ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string) (*Response, error)
+	ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string, rc *RequestContext) (*Response, error)
 	ResolvePath(method, swaggerTpl, actualPath, legacyFlatFilename string) (string, error)
 }
 
@@ -17,4 +19,41 @@ type IScenarioResolver interface {
 		actualPath string,
 	) (file string, state string, err error)
 	TryResetByRequest(method, actualPath string) bool
+	SetState(sc *Scenario, swaggerTpl, keyVal, state string) error
+	Reset(swaggerTpl, keyVal string) bool
+	ResetAll() int
+	States() []ScenarioState
+	Clock(swaggerTpl, keyVal string) (IVirtualClock, error)
+	Clocks() []ClockState
+}
+
+type ISessionStore interface {
+	// Resolver returns the scenario state of a session, creating the session on first use.
+	Resolver(id string) IScenarioResolver
+	Create(id string) (string, bool)
+	Delete(id string) bool
+	List() []SessionInfo
+}
+
+type IStubStore interface {
+	Add(st Stub) (Stub, error)
+	List() []Stub
+	Delete(id string) bool
+	// Reset drops the stubs of one session, or all stubs when session is empty.
+	Reset(session string) int
+	// Match returns the best stub for a request and counts the hit.
+	Match(method, swaggerTpl, actualPath string, rc *RequestContext) (Stub, bool)
+}
+
+type IClock interface {
+	Now() time.Time
+}
+
+type IVirtualClock interface {
+	IClock
+	Frozen() bool
+	Freeze()
+	Resume()
+	Advance(d time.Duration)
+	Set(t time.Time)
 }
@@ -4,18 +4,62 @@
 
 package samples
 
-import "github.com/greenbone/gvm-openapi-emulator/config"
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
 
 type Envelope struct {
 	Status  int               `json:"status"`
 	Headers map[string]string `json:"headers"`
 	Body    any               `json:"body"`
+	// BodyEncoding "base64" marks Body as the base64 form of a binary body.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+	// BodyFile serves a file, relative to the sample's folder, instead of Body.
+	BodyFile string `json:"bodyFile,omitempty"`
 }
 
 type Response struct {
 	Status  int
 	Headers map[string]string
 	Body    []byte
+
+	// Source is the sample file the response was loaded from.
+	Source string
+	// State is the scenario state that was served, empty outside scenarios.
+	State string
+}
+
+// RequestContext is the part of the incoming request that sample resolution can use.
+type RequestContext struct {
+	Query   url.Values
+	Headers http.Header
+	Body    []byte
+	// PathParams are the path parameter values found by the router; when nil
+	// they are extracted from the path again.
+	PathParams map[string]string
+	// Session selects isolated scenario state; empty means the shared default.
+	Session string
+	// Status is a preferred status code; it selects the <METHOD>.status-<status>.json sample.
+	Status int
+	// StubsOnly limits resolution to runtime stubs, for requests the spec answers otherwise.
+	StubsOnly bool
+}
+
+// TemplateData is the data a templated sample is executed with.
+// Headers are keyed by lower-case name; Query holds the first value per parameter;
+// Body is the parsed JSON request body, an empty object when absent or not JSON.
+type TemplateData struct {
+	Method  string
+	Path    map[string]string
+	Query   map[string]string
+	Headers map[string]string
+	Body    any
+	State   string
 }
 
 type ProviderConfig struct {
@@ -24,6 +68,20 @@ type ProviderConfig struct {
 	ScenarioEnabled  bool
 	ScenarioFilename string
 	ScenarioResolver IScenarioResolver
+	// Sessions hold per-session scenario state for requests that name a session.
+	Sessions ISessionStore
+
+	// Scenarios are preloaded scenarios keyed by swagger path template.
+	// Paths without an entry fall back to loading the scenario file per request.
+	Scenarios map[string]*Scenario
+
+	// Templates renders sample files as text/template before parsing them.
+	Templates bool
+	// Clock backs the template time helpers; nil means the wall clock.
+	Clock IClock
+
+	// Stubs are runtime stubs answered before scenarios and sample files.
+	Stubs IStubStore
 }
 
 type Scenario struct {
@@ -67,6 +125,69 @@ type MatchRule struct {
 	Path   string `json:"path,omitempty"`
 }
 
+// VariantSet is the content of a variants.json file: alternative responses of an
+// endpoint folder, picked by matching the request.
+type VariantSet struct {
+	Version  int       `json:"version"`
+	Variants []Variant `json:"variants"`
+}
+
+// Variant serves File when all predicates of Match hold. Among matching variants
+// the highest Priority wins, then the one with more predicates, then file order.
+type Variant struct {
+	Name     string       `json:"name,omitempty"`
+	Method   string       `json:"method"`
+	Priority int          `json:"priority,omitempty"`
+	Match    VariantMatch `json:"match"`
+	File     string       `json:"file"`
+}
+
+// VariantMatch holds predicates by request part. Query, Headers and Path are keyed
+// by parameter name (header names are case-insensitive); Body is keyed by a
+// JSONPath-like selector such as $.target.hosts[0].
+type VariantMatch struct {
+	Query   map[string]Matcher `json:"query,omitempty"`
+	Headers map[string]Matcher `json:"headers,omitempty"`
+	Path    map[string]Matcher `json:"path,omitempty"`
+	Body    map[string]Matcher `json:"body,omitempty"`
+}
+
+// Matcher is a single predicate. A plain JSON value means equality; an object
+// may set equals, regex (matched against the value's string form) or present.
+type Matcher struct {
+	Equals  any    `json:"equals,omitempty"`
+	Regex   string `json:"regex,omitempty"`
+	Present *bool  `json:"present,omitempty"`
+	re      *regexp.Regexp
+}
+
+// SessionInfo describes a session for the admin API.
+type SessionInfo struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Active is the number of scenario keys the session has touched.
+	Active int `json:"active"`
+}
+
+// Stub is a response registered at runtime. Method and Path narrow the requests
+// it answers (empty means any); Path is a literal path, or a route template when
+// it contains "{". Times limits how often it answers, 0 means unlimited.
+// A stub with a Session only answers requests of that session.
+type Stub struct {
+	ID       string       `json:"id"`
+	Session  string       `json:"session,omitempty"`
+	Method   string       `json:"method,omitempty"`
+	Path     string       `json:"path,omitempty"`
+	Match    VariantMatch `json:"match"`
+	Priority int          `json:"priority,omitempty"`
+	Times    int          `json:"times,omitempty"`
+	Response Envelope     `json:"response"`
+
+	Hits int `json:"hits"`
+	// seq orders stubs of equal priority, newest first
+	seq int64
+}
+
 type ResetRule struct {
 	Method  string
 	PathTpl string
@@ -76,3 +197,30 @@ type ResetBinding struct {
 	ScenarioTpl string
 	KeyParam    string
 }
+
+// ScenarioState describes the runtime state of one scenario key.
+// Index is the sequence position served next (step mode); ElapsedSec is the
+// time since the timer started (time mode, absent until started).
+type ScenarioState struct {
+	SwaggerPath string `json:"swaggerPath"`
+	Key         string `json:"key"`
+	Mode        string `json:"mode"`
+	State       string `json:"state"`
+	Index       *int   `json:"index,omitempty"`
+	ElapsedSec  *int64 `json:"elapsedSec,omitempty"`
+}
+
+// ClockState describes the virtual clock, or the clock of one scenario key
+// when SwaggerPath and Key are set.
+type ClockState struct {
+	SwaggerPath string    `json:"swaggerPath,omitempty"`
+	Key         string    `json:"key,omitempty"`
+	Now         time.Time `json:"now"`
+	Frozen      bool      `json:"frozen"`
+}
+
+type activeScenario struct {
+	swaggerTpl string
+	keyVal     string
+	sc         *Scenario
+}
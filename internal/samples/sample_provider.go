@@ -6,9 +6,12 @@ package samples
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/greenbone/gvm-openapi-emulator/utils"
@@ -17,6 +20,12 @@ import (
 	"github.com/greenbone/gvm-openapi-emulator/config"
 )
 
+var (
+	ErrInvalidSampleJSON = errors.New("invalid JSON")
+	ErrMalformedEnvelope = errors.New("malformed envelope")
+	ErrNoStub            = errors.New("no runtime stub matched")
+)
+
 type SampleProvider struct {
 	cfg ProviderConfig
 	log *logrus.Logger
@@ -26,64 +35,208 @@ func NewSampleProvider(cfg ProviderConfig, log *logrus.Logger) ISampleProvider {
 	return &SampleProvider{cfg: cfg, log: log}
 }
 
-func (p *SampleProvider) ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string) (*Response, error) {
-	path, err := p.ResolvePath(method, swaggerTpl, actualPath, legacyFlatFilename)
+func (p *SampleProvider) ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string, rc *RequestContext) (*Response, error) {
+	if p.cfg.Stubs != nil {
+		if st, ok := p.cfg.Stubs.Match(method, swaggerTpl, actualPath, rc); ok {
+			resp, err := p.stubResponse(st, NewTemplateData(method, swaggerTpl, actualPath, "", rc), p.templateClock(rc))
+			if err != nil {
+				return nil, err
+			}
+			p.log.WithFields(logrus.Fields{"stub": st.ID, "hits": st.Hits}).Debug("serving runtime stub")
+			resp.Source = StubSourcePrefix + st.ID
+			return resp, nil
+		}
+	}
+	if rc != nil && rc.StubsOnly {
+		return nil, ErrNoStub
+	}
+
+	path, state, err := p.resolve(method, swaggerTpl, actualPath, legacyFlatFilename, rc)
 	if err != nil {
 		p.log.WithError(err).Info("failed to resolve path")
 		return nil, err
 	}
-	return loadFile(path)
+
+	var resp *Response
+	if p.cfg.Templates && IsTextContentType(ContentTypeForFile(path)) {
+		resp, err = p.renderFile(path, NewTemplateData(method, swaggerTpl, actualPath, state, rc), p.templateClock(rc))
+	} else {
+		resp, err = loadFile(path, p.cfg.BaseDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Source = path
+	resp.State = state
+	return resp, nil
+}
+
+func (p *SampleProvider) renderFile(path string, data TemplateData, clock IClock) (*Response, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sample %s: %w", path, err)
+	}
+	if b, err = RenderTemplate(path, b, data, clock); err != nil {
+		return nil, fmt.Errorf("render sample %s: %w", path, err)
+	}
+	return parseSampleFile(path, b, p.cfg.BaseDir)
 }
 
 func (p *SampleProvider) ResolvePath(method, swaggerTpl, actualPath, legacyFlatFilename string) (string, error) {
+	path, _, err := p.resolve(method, swaggerTpl, actualPath, legacyFlatFilename, nil)
+	return path, err
+}
+
+// scenarioResolver returns the scenario state of the request's session.
+func (p *SampleProvider) scenarioResolver(rc *RequestContext) IScenarioResolver {
+	if rc != nil && rc.Session != "" && p.cfg.Sessions != nil {
+		return p.cfg.Sessions.Resolver(rc.Session)
+	}
+	return p.cfg.ScenarioResolver
+}
+
+// templateClock returns the clock templates render with: the session's own
+// virtual clock for requests that name a session, else the global one.
+func (p *SampleProvider) templateClock(rc *RequestContext) IClock {
+	if rc != nil && rc.Session != "" && p.cfg.Sessions != nil {
+		if c, err := p.cfg.Sessions.Resolver(rc.Session).Clock("", ""); err == nil {
+			return c
+		}
+	}
+	return p.cfg.Clock
+}
+
+// resolve finds the sample file of a request and, for scenarios, the state it belongs to.
+func (p *SampleProvider) resolve(method, swaggerTpl, actualPath, legacyFlatFilename string, rc *RequestContext) (string, string, error) {
 	cfg := p.cfg
 	method = strings.ToUpper(method)
 
+	// A preferred status code is answered by its own sample only, never by a
+	// scenario state or the plain sample
+	if rc != nil && rc.Status != 0 {
+		if cfg.Layout == config.LayoutFlat {
+			return "", "", fmt.Errorf("no sample file for status %d: per-status samples need the folder layout", rc.Status)
+		}
+		rel := filepath.Join(filepath.FromSlash(strings.TrimPrefix(swaggerTpl, "/")), StatusSampleName(method, rc.Status))
+		if full, ok := findSample(cfg.BaseDir, rel); ok {
+			return full, "", nil
+		}
+		return "", "", fmt.Errorf("no sample file for status %d (tried: %s)", rc.Status, rel)
+	}
+
 	// Scenario priority
 	if cfg.ScenarioEnabled {
+		resolver := p.scenarioResolver(rc)
 		scPath := ScenarioPathForSwagger(cfg.BaseDir, swaggerTpl, cfg.ScenarioFilename)
 		if utils.FileExists(scPath) {
-			sc, err := LoadScenario(scPath)
-			if err != nil {
-				p.log.WithError(err).Warn("failed to load scenario")
-				return "", fmt.Errorf("load scenario %s: %w", scPath, err)
+			sc, ok := cfg.Scenarios[swaggerTpl]
+			if !ok {
+				var err error
+				sc, err = LoadScenario(scPath)
+				if err != nil {
+					p.log.WithError(err).Warn("failed to load scenario")
+					return "", "", fmt.Errorf("load scenario %s: %w", scPath, err)
+				}
 			}
-			if cfg.ScenarioResolver == nil {
-				return "", fmt.Errorf("scenario enabled but engine is nil")
+			if resolver == nil {
+				return "", "", fmt.Errorf("scenario enabled but engine is nil")
 			}
 
-			file, _, err := cfg.ScenarioResolver.ResolveScenarioFile(sc, method, swaggerTpl, actualPath)
+			file, state, err := resolver.ResolveScenarioFile(sc, method, swaggerTpl, actualPath)
 			if err != nil {
 				p.log.WithError(err).Warn("failed to resolve scenario")
-				return "", fmt.Errorf("scenario resolve: %w", err)
+				return "", "", fmt.Errorf("scenario resolve: %w", err)
 			}
 
 			full := filepath.Join(filepath.Dir(scPath), file)
 			if utils.FileExists(full) {
-				return full, nil
+				return full, state, nil
 			}
-			return "", fmt.Errorf("scenario file not found: %s", full)
+			return "", "", fmt.Errorf("scenario file not found: %s", full)
 		}
-		if cfg.ScenarioEnabled && cfg.ScenarioResolver != nil {
-			_ = cfg.ScenarioResolver.TryResetByRequest(method, actualPath)
+		if resolver != nil {
+			_ = resolver.TryResetByRequest(method, actualPath)
+		}
+	}
+
+	// Request-matched variants of the endpoint folder
+	if cfg.Layout != config.LayoutFlat {
+		dir := filepath.Join(cfg.BaseDir, filepath.FromSlash(strings.TrimPrefix(swaggerTpl, "/")))
+		vPath := filepath.Join(dir, VariantsFilename)
+		if utils.FileExists(vPath) {
+			vs, err := LoadVariants(vPath)
+			if err != nil {
+				p.log.WithError(err).Warn("failed to load variants")
+				return "", "", fmt.Errorf("load variants %s: %w", vPath, err)
+			}
+			if v := vs.Select(method, NewTemplateData(method, swaggerTpl, actualPath, "", rc)); v != nil {
+				full := filepath.Join(dir, v.File)
+				if !utils.FileExists(full) {
+					return "", "", fmt.Errorf("variant file not found: %s", full)
+				}
+				p.log.WithFields(logrus.Fields{"variant": v.Name, "file": v.File}).Debug("serving matched variant")
+				return full, "", nil
+			}
 		}
 	}
 
 	// Non-scenario fallback: folder/flat
 	candidates := buildCandidates(cfg.Layout, method, swaggerTpl, legacyFlatFilename)
 	if len(candidates) == 0 {
-		return "", fmt.Errorf("no candidates for method=%s path=%s", method, swaggerTpl)
+		return "", "", fmt.Errorf("no candidates for method=%s path=%s", method, swaggerTpl)
 	}
 
-	for _, rel := range candidates {
+	for i, rel := range candidates {
+		if cfg.Layout != config.LayoutFlat && i == 0 {
+			// the folder sample may be <METHOD>.xml, <METHOD>.pdf, ...
+			if full, ok := findSample(cfg.BaseDir, rel); ok {
+				return full, "", nil
+			}
+			continue
+		}
 		full := filepath.Join(cfg.BaseDir, rel)
 		if utils.FileExists(full) {
-			return full, nil
+			return full, "", nil
 		}
 	}
 
 	p.log.WithField("path", actualPath).Info("no sample found; caller may fallback to spec example")
-	return "", fmt.Errorf("no sample file found (tried: %v)", candidates)
+	return "", "", fmt.Errorf("no sample file found (tried: %v)", candidates)
+}
+
+// statusStatePrefix marks per-status samples, keeping them apart from scenario
+// states, which may be numbered (recorded scenarios use 1, 2, ...).
+const statusStatePrefix = "status-"
+
+// StatusSampleName is the folder sample served when a request prefers a status code,
+// e.g. GET.status-404.json.
+func StatusSampleName(method string, status int) string {
+	return fmt.Sprintf("%s.%s%d.json", strings.ToUpper(method), statusStatePrefix, status)
+}
+
+// IsStatusState reports whether the state part of a sample name, as in GET.<state>.json,
+// names a status code (status-404), i.e. the file is a per-status sample rather than
+// a scenario state.
+func IsStatusState(state string) bool {
+	code, ok := strings.CutPrefix(state, statusStatePrefix)
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(code)
+	return err == nil && len(code) == 3 && n >= 100 && n <= 599
+}
+
+// findSample looks for the .json sample rel below baseDir, then for the same name
+// with the other SampleExtensions.
+func findSample(baseDir, rel string) (string, bool) {
+	stem := strings.TrimSuffix(rel, ".json")
+	for _, ext := range SampleExtensions {
+		full := filepath.Join(baseDir, stem+ext)
+		if utils.FileExists(full) {
+			return full, true
+		}
+	}
+	return "", false
 }
 
 func buildCandidates(layout config.LayoutMode, method, swaggerPath, legacyFlatFilename string) []string {
@@ -103,11 +256,32 @@ func buildCandidates(layout config.LayoutMode, method, swaggerPath, legacyFlatFi
 	return out
 }
 
-func loadFile(path string) (*Response, error) {
-	b, err := os.ReadFile(path)
+// loadFile reads a sample file; an envelope's bodyFile must stay below root.
+func loadFile(path, root string) (*Response, error) {
+	b, err := os.ReadFile(path) // #nosec G304 -- resolved below the samples dir
 	if err != nil {
 		return nil, fmt.Errorf("read sample %s: %w", path, err)
 	}
+	return parseSampleFile(path, b, root)
+}
+
+// parseSampleFile parses a .json sample file and serves any other file as it is,
+// with the content type of its extension.
+func parseSampleFile(path string, b []byte, root string) (*Response, error) {
+	ct := ContentTypeForFile(path)
+	if !IsJSONContentType(ct) {
+		return &Response{
+			Status:  200,
+			Headers: map[string]string{"content-type": ct},
+			Body:    b,
+		}, nil
+	}
+	return parseSample(b, filepath.Dir(path), root)
+}
+
+// parseSample turns sample bytes into a response: an envelope, or a plain JSON body.
+// An envelope's bodyFile is resolved against dir and must stay below root.
+func parseSample(b []byte, dir, root string) (*Response, error) {
 	raw := strings.TrimSpace(string(b))
 	if raw == "" {
 		return &Response{
@@ -130,18 +304,15 @@ func loadFile(path string) (*Response, error) {
 				headers = map[string]string{}
 			}
 
-			if _, ok := headerGet(headers, "content-type"); !ok {
-				headers["content-type"] = "application/json"
+			ct, ok := headerGet(headers, "content-type")
+			if !ok {
+				ct = envelopeContentType(env)
+				headers["content-type"] = ct
 			}
 
-			var bodyBytes []byte
-			if env.Body == nil {
-				bodyBytes = []byte("{}")
-			} else {
-				bodyBytes, err = json.Marshal(env.Body)
-				if err != nil {
-					return nil, fmt.Errorf("marshal envelope body: %w", err)
-				}
+			bodyBytes, err := envelopeBody(env, ct, dir, root)
+			if err != nil {
+				return nil, err
 			}
 
 			return &Response{
@@ -159,6 +330,67 @@ func loadFile(path string) (*Response, error) {
 	}, nil
 }
 
+// CheckSample reports whether raw sample bytes are served the way they look.
+// It returns ErrInvalidSampleJSON for unparsable files and ErrMalformedEnvelope for
+// envelopes that would be served raw or would silently drop fields.
+func CheckSample(raw []byte) error {
+	s := strings.TrimSpace(string(raw))
+	if s == "" {
+		return nil
+	}
+	if !json.Valid([]byte(s)) {
+		return ErrInvalidSampleJSON
+	}
+	if !isJSONObject(s) || !looksLikeEnvelope([]byte(s)) {
+		return nil
+	}
+
+	var m map[string]json.RawMessage
+	_ = json.Unmarshal([]byte(s), &m)
+
+	var unknown []string
+	for k := range m {
+		if !envelopeKeys[k] {
+			unknown = append(unknown, k)
+		}
+	}
+
+	var env Envelope
+	if err := json.Unmarshal([]byte(s), &env); err != nil {
+		_, hasHeaders := m["headers"]
+		_, hasBody := m["body"]
+		_, hasBodyFile := m["bodyFile"]
+		if len(unknown) > 0 || (!hasHeaders && !hasBody && !hasBodyFile) {
+			// a plain body that happens to use an envelope key, e.g. {"status":"running"}
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrMalformedEnvelope, err)
+	}
+
+	if env.Status != 0 && (env.Status < 100 || env.Status > 599) {
+		return fmt.Errorf("%w: invalid status %d", ErrMalformedEnvelope, env.Status)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%w: unknown fields %v are ignored", ErrMalformedEnvelope, unknown)
+	}
+	if env.BodyFile != "" && (env.Body != nil || env.BodyEncoding != "") {
+		return fmt.Errorf("%w: body and bodyEncoding are ignored with bodyFile", ErrMalformedEnvelope)
+	}
+	if env.BodyFile == "" {
+		ct, ok := headerGet(env.Headers, "content-type")
+		if !ok {
+			ct = envelopeContentType(env)
+		}
+		if _, err := envelopeBody(env, ct, "", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var envelopeKeys = map[string]bool{"status": true, "headers": true, "body": true, "bodyEncoding": true, "bodyFile": true}
+
 func isJSONObject(s string) bool {
 	s = strings.TrimSpace(s)
 	return strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}")
@@ -172,7 +404,8 @@ func looksLikeEnvelope(raw []byte) bool {
 	_, hasStatus := m["status"]
 	_, hasHeaders := m["headers"]
 	_, hasBody := m["body"]
-	return hasStatus || hasHeaders || hasBody
+	_, hasBodyFile := m["bodyFile"]
+	return hasStatus || hasHeaders || hasBody || hasBodyFile
 }
 
 func headerGet(h map[string]string, key string) (string, bool) {
@@ -39,6 +39,39 @@ func (m *MockScenarioResolver) TryResetByRequest(method, actualPath string) bool
 	return args.Bool(0)
 }
 
+func (m *MockScenarioResolver) SetState(sc *Scenario, swaggerTpl, keyVal, state string) error {
+	args := m.Called(sc, swaggerTpl, keyVal, state)
+	return args.Error(0)
+}
+
+func (m *MockScenarioResolver) Reset(swaggerTpl, keyVal string) bool {
+	args := m.Called(swaggerTpl, keyVal)
+	return args.Bool(0)
+}
+
+func (m *MockScenarioResolver) ResetAll() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+func (m *MockScenarioResolver) States() []ScenarioState {
+	args := m.Called()
+	st, _ := args.Get(0).([]ScenarioState)
+	return st
+}
+
+func (m *MockScenarioResolver) Clock(swaggerTpl, keyVal string) (IVirtualClock, error) {
+	args := m.Called(swaggerTpl, keyVal)
+	c, _ := args.Get(0).(IVirtualClock)
+	return c, args.Error(1)
+}
+
+func (m *MockScenarioResolver) Clocks() []ClockState {
+	args := m.Called()
+	st, _ := args.Get(0).([]ClockState)
+	return st
+}
+
 func writeFile(t *testing.T, dir, name, content string) string {
 	t.Helper()
 	p := filepath.Join(dir, name)
@@ -50,7 +83,7 @@ func writeFile(t *testing.T, dir, name, content string) string {
 }
 
 func TestLoadFile_ReadError(t *testing.T) {
-	_, err := loadFile("/no/such/dir/missing.json")
+	_, err := loadFile("/no/such/dir/missing.json", "/no/such/dir")
 	require.Error(t, err)
 }
 
@@ -58,7 +91,7 @@ func TestLoadFile_EmptyFile_ReturnsDefault(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "empty.json", "   \n\t  ")
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, dir)
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
@@ -70,7 +103,7 @@ func TestLoadFile_Envelope_DefaultsApplied(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "sample.json", `{"body":{"ok":true}}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, dir)
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
@@ -86,7 +119,7 @@ func TestLoadFile_Envelope_ExplicitValues(t *testing.T) {
 	  "body": {"id": 123}
 	}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, dir)
 	require.NoError(t, err)
 
 	require.Equal(t, 201, resp.Status)
@@ -99,7 +132,7 @@ func TestLoadFile_Envelope_StatusOnly_DefaultsHeaderAndBody(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "sample.json", `{"status":204}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, dir)
 	require.NoError(t, err)
 
 	require.Equal(t, 204, resp.Status)
@@ -111,12 +144,12 @@ func TestLoadFile_Envelope_HeadersPresentButBodyMissing(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "hdrs.json", `{"headers":{"content-type":"text/plain"}}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, dir)
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
 	require.Equal(t, "text/plain", resp.Headers["content-type"])
-	require.Equal(t, `{}`, string(resp.Body))
+	require.Empty(t, resp.Body)
 }
 
 func TestLoadFile_Envelope_ContentTypeNotInjectedIfAlreadyPresentCaseInsensitive(t *testing.T) {
@@ -126,7 +159,7 @@ func TestLoadFile_Envelope_ContentTypeNotInjectedIfAlreadyPresentCaseInsensitive
 	  "body": {"ok": true}
 	}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, dir)
 	require.NoError(t, err)
 
 	require.Equal(t, "text/plain", resp.Headers["Content-Type"])
@@ -141,7 +174,7 @@ func TestLoadFile_Fallback_RawJSONOrText(t *testing.T) {
 	t.Run("raw json without envelope", func(t *testing.T) {
 		p := writeFile(t, dir, "raw.json", `{}`)
 
-		resp, err := loadFile(p)
+		resp, err := loadFile(p, dir)
 		require.NoError(t, err)
 
 		require.Equal(t, 200, resp.Status)
@@ -152,15 +185,83 @@ func TestLoadFile_Fallback_RawJSONOrText(t *testing.T) {
 	t.Run("plain text", func(t *testing.T) {
 		p := writeFile(t, dir, "raw.txt", `  hello world  `)
 
-		resp, err := loadFile(p)
+		resp, err := loadFile(p, dir)
 		require.NoError(t, err)
 
 		require.Equal(t, 200, resp.Status)
-		require.Equal(t, "application/json", resp.Headers["content-type"])
-		require.Equal(t, `hello world`, string(resp.Body))
+		require.Equal(t, "text/plain; charset=utf-8", resp.Headers["content-type"])
+		require.Equal(t, `  hello world  `, string(resp.Body))
 	})
 }
 
+func TestLoadFile_NonJSONBodies(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "report.pdf", "%PDF-1.4\n\x00\x01")
+
+	cases := []struct {
+		name   string
+		sample string
+		ct     string
+		body   string
+	}{
+		{"xml string body", `{"headers":{"content-type":"application/xml"},"body":"<report/>"}`, "application/xml", "<report/>"},
+		{"json string body stays quoted", `{"body":"abc"}`, "application/json", `"abc"`},
+		{"base64 body", `{"bodyEncoding":"base64","body":"AAEC"}`, "application/octet-stream", "\x00\x01\x02"},
+		{"body file", `{"status":200,"bodyFile":"report.pdf"}`, "application/pdf", "%PDF-1.4\n\x00\x01"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := loadFile(writeFile(t, dir, "sample.json", tc.sample), dir)
+			require.NoError(t, err)
+			require.Equal(t, tc.ct, resp.Headers["content-type"])
+			require.Equal(t, tc.body, string(resp.Body))
+		})
+	}
+
+	_, err := loadFile(writeFile(t, dir, "missing.json", `{"bodyFile":"nope.pdf"}`), dir)
+	require.ErrorIs(t, err, ErrBodyFile)
+}
+
+func TestSampleProvider_ResolveAndLoad_BodyFileStaysInSamplesDir(t *testing.T) {
+	root := t.TempDir()
+	baseDir := filepath.Join(root, "samples")
+	writeFile(t, root, "secret.txt", "top secret")
+	writeFile(t, baseDir, filepath.Join("shared", "report.xml"), "<report/>")
+	writeFile(t, baseDir, filepath.Join("reports", "GET.json"), `{"bodyFile":"../shared/report.xml"}`)
+	writeFile(t, baseDir, filepath.Join("secrets", "GET.json"), `{"bodyFile":"../../secret.txt"}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/reports", "/reports", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "<report/>", string(resp.Body))
+
+	_, err = p.ResolveAndLoad("GET", "/secrets", "/secrets", "", nil)
+	require.ErrorIs(t, err, ErrBodyFile)
+	require.ErrorContains(t, err, "outside the samples dir")
+}
+
+func TestSampleProvider_ResolveAndLoad_BodyFileSymlinkStaysInSamplesDir(t *testing.T) {
+	root := t.TempDir()
+	baseDir := filepath.Join(root, "samples")
+	writeFile(t, root, "secret.txt", "top secret")
+	writeFile(t, baseDir, filepath.Join("shared", "report.xml"), "<report/>")
+	writeFile(t, baseDir, filepath.Join("leak", "GET.json"), `{"bodyFile":"secret.txt"}`)
+	writeFile(t, baseDir, filepath.Join("linked", "GET.json"), `{"bodyFile":"report.xml"}`)
+	require.NoError(t, os.Symlink(filepath.Join(root, "secret.txt"), filepath.Join(baseDir, "leak", "secret.txt")))
+	require.NoError(t, os.Symlink(filepath.Join(baseDir, "shared", "report.xml"), filepath.Join(baseDir, "linked", "report.xml")))
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	_, err := p.ResolveAndLoad("GET", "/leak", "/leak", "", nil)
+	require.ErrorIs(t, err, ErrBodyFile)
+	require.ErrorContains(t, err, "outside the samples dir")
+
+	resp, err := p.ResolveAndLoad("GET", "/linked", "/linked", "", nil)
+	require.NoError(t, err, "a symlink within the samples dir is served")
+	require.Equal(t, "<report/>", string(resp.Body))
+}
+
 func TestBuildCandidates_LayoutFolders(t *testing.T) {
 	got := buildCandidates(config.LayoutFolders, "GET", "/api/v1/items", "GET_api_v1_items.json")
 	require.Equal(t, []string{filepath.Join("api", "v1", "items", "GET.json")}, got)
@@ -194,12 +295,38 @@ func TestSampleProvider_ResolveAndLoad_FoldersMode_LoadsFolderSample(t *testing.
 		Layout:  config.LayoutFolders,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
 	require.Equal(t, "application/json", resp.Headers["content-type"])
 	require.Equal(t, `{"ok":true}`, string(resp.Body))
+	require.Equal(t, filepath.Join(baseDir, "api", "v1", "items", "GET.json"), resp.Source)
+}
+
+func TestSampleProvider_ResolveAndLoad_NonJSONFolderSamples(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, filepath.Join("reports", "{id}", "GET.xml"), `<report id="{{ .Path.id }}"/>`)
+	writeFile(t, baseDir, filepath.Join("reports", "{id}", "GET.status-404.txt"), "not found\n")
+	writeFile(t, baseDir, filepath.Join("reports", "{id}", "pdf", "GET.pdf"), "%PDF-1.4 {{")
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders, Templates: true}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/reports/{id}", "/reports/r1", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "application/xml", resp.Headers["content-type"])
+	require.Equal(t, `<report id="r1"/>`, string(resp.Body))
+
+	resp, err = p.ResolveAndLoad("GET", "/reports/{id}", "/reports/r1", "", &RequestContext{Status: 404})
+	require.NoError(t, err)
+	require.Equal(t, "text/plain; charset=utf-8", resp.Headers["content-type"])
+	require.Equal(t, "not found\n", string(resp.Body))
+
+	// binary files are never templated
+	resp, err = p.ResolveAndLoad("GET", "/reports/{id}/pdf", "/reports/r1/pdf", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "application/pdf", resp.Headers["content-type"])
+	require.Equal(t, "%PDF-1.4 {{", string(resp.Body))
 }
 
 func TestSampleProvider_ResolveAndLoad_FlatMode_LoadsLegacyFlatSample(t *testing.T) {
@@ -217,7 +344,7 @@ func TestSampleProvider_ResolveAndLoad_FlatMode_LoadsLegacyFlatSample(t *testing
 		Layout:  config.LayoutFlat,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, `{"from":"flat"}`, string(resp.Body))
@@ -239,7 +366,7 @@ func TestSampleProvider_ResolveAndLoad_AutoMode_PrefersFoldersOverFlat(t *testin
 		Layout:  config.LayoutAuto,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, `{"from":"folders"}`, string(resp.Body))
@@ -294,13 +421,48 @@ func TestSampleProvider_ScenarioEnabled_UsesScenarioEngine(t *testing.T) {
 		ScenarioResolver: m,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, nil)
 	require.NoError(t, err)
 	require.Equal(t, `{"from":"scenario"}`, string(resp.Body))
 
 	m.AssertExpectations(t)
 }
 
+func TestSampleProvider_ScenarioEnabled_UsesPreloadedScenario(t *testing.T) {
+	baseDir := t.TempDir()
+
+	swaggerTpl := "/api/v1/items/{id}"
+	actualPath := "/api/v1/items/123"
+
+	scPath := ScenarioPathForSwagger(baseDir, swaggerTpl, "scenario.json")
+	// unparsable on disk: only the preloaded copy may be used
+	writeFile(t, filepath.Dir(scPath), filepath.Base(scPath), `{not json`)
+	writeFile(t, filepath.Dir(scPath), "GET.requested.json", `{"body":{"from":"preloaded"}}`)
+
+	preloaded := &Scenario{Version: 1, Mode: "step", Sequence: []ScenarioEntry{{State: "requested", File: "GET.requested.json"}}}
+	preloaded.Key.PathParam = "id"
+
+	m := new(MockScenarioResolver)
+	m.On("ResolveScenarioFile", preloaded, "GET", swaggerTpl, actualPath).
+		Return("GET.requested.json", "requested", nil).
+		Once()
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutAuto,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenarioResolver: m,
+		Scenarios:        map[string]*Scenario{swaggerTpl: preloaded},
+	}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", swaggerTpl, actualPath, "GET_api_v1_items_{id}.json", nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"from":"preloaded"}`, string(resp.Body))
+
+	m.AssertExpectations(t)
+}
+
 func TestSampleProvider_ScenarioEnabled_EngineNil_ReturnsError(t *testing.T) {
 	baseDir := t.TempDir()
 
@@ -453,9 +615,77 @@ func TestSampleProvider_ScenarioEnabled_ScenarioFileExists_DoesNotCallTryResetBy
 		ScenarioResolver: m,
 	}, logger.GetLogger())
 
-	_, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	_, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, nil)
 	require.NoError(t, err)
 
 	m.AssertNotCalled(t, "TryResetByRequest", mock.Anything, mock.Anything)
 	m.AssertExpectations(t)
 }
+
+func TestCheckSample(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want error
+	}{
+		{"empty", "  ", nil},
+		{"raw object", `{"id":"1"}`, nil},
+		{"raw array", `["a","b"]`, nil},
+		{"raw string", `"abc"`, nil},
+		{"raw body using status key", `{"status":"running","host_info":{}}`, nil},
+		{"status only string", `{"status":"requested"}`, nil},
+		{"envelope", `{"status":201,"headers":{"x":"1"},"body":{"ok":true}}`, nil},
+		{"invalid json", `{"status":201`, ErrInvalidSampleJSON},
+		{"wrong status type", `{"status":"201","body":{}}`, ErrMalformedEnvelope},
+		{"wrong headers type", `{"headers":{"x":1},"body":{}}`, ErrMalformedEnvelope},
+		{"status out of range", `{"status":42,"body":{}}`, ErrMalformedEnvelope},
+		{"ignored fields", `{"status":200,"body":{},"delay":5}`, ErrMalformedEnvelope},
+		{"base64 body", `{"bodyEncoding":"base64","body":"JVBERi0="}`, nil},
+		{"invalid base64", `{"bodyEncoding":"base64","body":"%%%"}`, ErrMalformedEnvelope},
+		{"unknown encoding", `{"bodyEncoding":"gzip","body":"x"}`, ErrMalformedEnvelope},
+		{"body file", `{"headers":{"content-type":"application/pdf"},"bodyFile":"report.pdf"}`, nil},
+		{"body file and body", `{"bodyFile":"report.pdf","body":{}}`, ErrMalformedEnvelope},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckSample([]byte(tc.raw))
+			if tc.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestSampleProvider_ResolveAndLoad_PreferredStatus(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, filepath.Join("scans", "{id}", "GET.json"), `{"id":"abc"}`)
+	writeFile(t, baseDir, filepath.Join("scans", "{id}", "GET.status-404.json"), `{"status":404,"body":{"error":"not found"}}`)
+
+	load := func(layout config.LayoutMode, status int) (*Response, error) {
+		p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: layout}, logger.GetLogger())
+		return p.ResolveAndLoad("GET", "/scans/{id}", "/scans/abc", "GET__scans_{id}.json", &RequestContext{Status: status})
+	}
+
+	resp, err := load(config.LayoutFolders, 404)
+	require.NoError(t, err)
+	require.Equal(t, 404, resp.Status)
+
+	_, err = load(config.LayoutFolders, 409)
+	require.ErrorContains(t, err, "no sample file for status 409", "the plain sample does not answer another status")
+
+	_, err = load(config.LayoutFlat, 404)
+	require.ErrorContains(t, err, "folder layout")
+
+	resp, err = load(config.LayoutFolders, 0)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.Status)
+
+	require.True(t, IsStatusState("status-404"))
+	require.False(t, IsStatusState("404"), "numbered scenario states are no status samples")
+	require.False(t, IsStatusState("running"))
+	require.False(t, IsStatusState("status-1000"))
+	require.False(t, IsStatusState("status-099"))
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ScenarioValidationError aggregates every problem found while preloading scenarios.
+type ScenarioValidationError struct {
+	Problems []string
+}
+
+func (e *ScenarioValidationError) Error() string {
+	return fmt.Sprintf("%d scenario problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// LoadScenarios discovers every scenario file under baseDir, parses it and checks it
+// against the spec operations (swagger path template -> HTTP methods).
+// The returned map holds every parsable scenario keyed by its swagger path template,
+// even when the error reports problems, so callers can decide to only warn.
+func LoadScenarios(baseDir, filename string, operations map[string][]string) (map[string]*Scenario, error) {
+	out := map[string]*Scenario{}
+	var problems []string
+
+	err := filepath.WalkDir(baseDir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return walkErr
+		}
+		if d.IsDir() || d.Name() != filename {
+			return nil
+		}
+
+		rel, err := filepath.Rel(baseDir, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = ""
+		}
+		tpl := path.Clean("/" + filepath.ToSlash(rel))
+
+		if _, ok := operations[tpl]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: folder %s does not match any path in the spec", p, tpl))
+			return nil
+		}
+
+		sc, err := LoadScenario(p)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", p, err))
+			return nil
+		}
+
+		for _, msg := range validateScenario(sc, tpl, filepath.Dir(p), operations) {
+			problems = append(problems, fmt.Sprintf("%s: %s", p, msg))
+		}
+		out[tpl] = sc
+		return nil
+	})
+	if err != nil {
+		return out, fmt.Errorf("discover scenarios: %w", err)
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return out, &ScenarioValidationError{Problems: problems}
+	}
+	return out, nil
+}
+
+func validateScenario(sc *Scenario, swaggerTpl, dir string, operations map[string][]string) []string {
+	var out []string
+
+	if !hasPathParam(swaggerTpl, sc.Key.PathParam) {
+		out = append(out, fmt.Sprintf("key.pathParam %q is not a parameter of %s", sc.Key.PathParam, swaggerTpl))
+	}
+
+	var files []string
+	for _, e := range sc.Sequence {
+		files = append(files, e.File)
+	}
+	for _, e := range sc.Timeline {
+		files = append(files, e.File)
+	}
+	for _, f := range files {
+		if strings.TrimSpace(f) == "" {
+			out = append(out, "state entry without file")
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+			out = append(out, fmt.Sprintf("referenced file %q does not exist", f))
+		}
+	}
+
+	check := func(kind string, rules []MatchRule) {
+		for _, r := range rules {
+			method := strings.ToUpper(strings.TrimSpace(r.Method))
+			tpl := strings.TrimSpace(r.Path)
+			if tpl == "" {
+				tpl = swaggerTpl
+			}
+
+			switch {
+			case method == "":
+				out = append(out, fmt.Sprintf("%s rule without method", kind))
+			case !hasOperation(operations, tpl, method):
+				out = append(out, fmt.Sprintf("%s rule %s %s does not match any route in the spec", kind, method, tpl))
+			case kind == "resetOn" && !hasPathParam(tpl, sc.Key.PathParam):
+				out = append(out, fmt.Sprintf("resetOn rule %s %s cannot carry key param %q", method, tpl, sc.Key.PathParam))
+			}
+		}
+	}
+	check("advanceOn", sc.Behavior.AdvanceOn)
+	check("resetOn", sc.Behavior.ResetOn)
+	check("startOn", sc.Behavior.StartOn)
+
+	return out
+}
+
+func hasOperation(operations map[string][]string, swaggerTpl, method string) bool {
+	for _, m := range operations[swaggerTpl] {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPathParam(swaggerTpl, name string) bool {
+	for _, p := range strings.Split(strings.Trim(swaggerTpl, "/"), "/") {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") &&
+			strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}") == name {
+			return true
+		}
+	}
+	return false
+}
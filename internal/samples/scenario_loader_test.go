@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var loaderOperations = map[string][]string{
+	"/scans":             {"POST"},
+	"/scans/{id}":        {"GET", "DELETE"},
+	"/scans/{id}/status": {"GET"},
+}
+
+func TestLoadScenarios_ValidTree(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "scans", "{id}", "status")
+
+	writeF(t, filepath.Join(dir, "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam":"id"},
+	  "sequence": [{"state":"requested","file":"GET.requested.json"}],
+	  "behavior": {
+		"advanceOn": [{"method":"GET"}],
+		"resetOn": [{"method":"DELETE","path":"/scans/{id}"}]
+	  }
+	}`)
+	writeF(t, filepath.Join(dir, "GET.requested.json"), `{}`)
+
+	got, err := LoadScenarios(base, "scenario.json", loaderOperations)
+	if err != nil {
+		t.Fatalf("LoadScenarios: %v", err)
+	}
+	sc, ok := got["/scans/{id}/status"]
+	if !ok || sc.Mode != "step" {
+		t.Fatalf("expected preloaded scenario, got %#v", got)
+	}
+}
+
+func TestLoadScenarios_MissingBaseDir(t *testing.T) {
+	got, err := LoadScenarios(filepath.Join(t.TempDir(), "nope"), "scenario.json", loaderOperations)
+	if err != nil {
+		t.Fatalf("expected no error for missing dir, got %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no scenarios, got %#v", got)
+	}
+}
+
+func TestLoadScenarios_AggregatesProblems(t *testing.T) {
+	base := t.TempDir()
+
+	writeF(t, filepath.Join(base, "scans", "{id}", "status", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "time",
+	  "key": {"pathParam":"scan"},
+	  "timeline": [{"afterSec":0,"state":"requested","file":"GET.requested.json"}],
+	  "behavior": {
+		"startOn": [{"method":"POST"}],
+		"resetOn": [{"method":"DELETE","path":"/scans/{id}"},{"method":"PUT","path":"/scans/{id}"}],
+		"advanceOn": [{"path":"/scans"}]
+	  }
+	}`)
+	writeF(t, filepath.Join(base, "scans", "{id}", "scenario.json"), `{"version": 2}`)
+	writeF(t, filepath.Join(base, "unknown", "scenario.json"), `{}`)
+
+	got, err := LoadScenarios(base, "scenario.json", loaderOperations)
+
+	var verr *ScenarioValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ScenarioValidationError, got %v", err)
+	}
+
+	want := []string{
+		`key.pathParam "scan" is not a parameter of /scans/{id}/status`,
+		`referenced file "GET.requested.json" does not exist`,
+		`startOn rule POST /scans/{id}/status does not match any route in the spec`,
+		`resetOn rule DELETE /scans/{id} cannot carry key param "scan"`,
+		`resetOn rule PUT /scans/{id} does not match any route in the spec`,
+		`advanceOn rule without method`,
+		`unsupported scenario version: 2`,
+		`folder /unknown does not match any path in the spec`,
+	}
+	msg := err.Error()
+	for _, w := range want {
+		if !strings.Contains(msg, w) {
+			t.Fatalf("expected %q in:\n%s", w, msg)
+		}
+	}
+	if len(verr.Problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d:\n%s", len(want), len(verr.Problems), msg)
+	}
+
+	// parsable scenarios are still returned for warn mode
+	if _, ok := got["/scans/{id}/status"]; !ok {
+		t.Fatalf("expected parsable scenario to be returned, got %#v", got)
+	}
+	if _, ok := got["/scans/{id}"]; ok {
+		t.Fatalf("expected unparsable scenario to be skipped")
+	}
+}
@@ -6,9 +6,11 @@ package samples
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -17,24 +19,53 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	ErrUnknownState    = errors.New("unknown scenario state")
+	ErrClockNotVirtual = errors.New("scenario clock is not virtual")
+)
+
 // ScenarioResolver holds runtime state (in-memory).
 type ScenarioResolver struct {
 	mu            sync.Mutex
 	stepIndex     map[string]int
 	startedAt     map[string]time.Time
+	active        map[string]activeScenario
 	resetRules    map[string][]ResetRule
 	resetByMethod map[string][]struct {
 		rule    ResetRule
 		binding ResetBinding
 	}
 
+	// clock drives time-mode scenarios; keyClocks override it per runtime key
+	// and are only available when clock is virtual.
+	clock     IClock
+	keyClocks map[string]keyClock
+
 	log *logrus.Logger
 }
 
+type keyClock struct {
+	swaggerTpl string
+	keyVal     string
+	clock      IVirtualClock
+}
+
 func NewScenarioResolver() IScenarioResolver {
+	return NewScenarioResolverWithClock(RealClock{})
+}
+
+// NewScenarioResolverWithClock creates a resolver whose time-mode scenarios follow clock.
+// Pass a virtual clock to make timelines controllable at runtime.
+func NewScenarioResolverWithClock(clock IClock) IScenarioResolver {
+	if clock == nil {
+		clock = RealClock{}
+	}
 	return &ScenarioResolver{
+		clock:      clock,
+		keyClocks:  map[string]keyClock{},
 		stepIndex:  map[string]int{},
 		startedAt:  map[string]time.Time{},
+		active:     map[string]activeScenario{},
 		resetRules: map[string][]ResetRule{},
 		resetByMethod: map[string][]struct {
 			rule    ResetRule
@@ -121,6 +152,23 @@ func (e *ScenarioResolver) ResolveScenarioFile(
 	k := scenarioRuntimeKey(swaggerTpl, keyVal)
 
 	e.mu.Lock()
+	e.activate(k, sc, swaggerTpl, keyVal)
+	e.mu.Unlock()
+
+	switch sc.Mode {
+	case "step":
+		return e.resolveStep(k, sc, method)
+	case "time":
+		return e.resolveTime(k, sc, method, actualPath)
+	default:
+		return "", "", fmt.Errorf("unsupported mode %q", sc.Mode)
+	}
+}
+
+// activate records the key as active and binds its resetOn rules. Callers hold e.mu.
+func (e *ScenarioResolver) activate(k string, sc *Scenario, swaggerTpl, keyVal string) {
+	e.active[k] = activeScenario{swaggerTpl: swaggerTpl, keyVal: keyVal, sc: sc}
+
 	if _, ok := e.resetRules[k]; !ok {
 		var rules []ResetRule
 		for _, r := range sc.Behavior.ResetOn {
@@ -158,17 +206,6 @@ func (e *ScenarioResolver) ResolveScenarioFile(
 			})
 		}
 	}
-
-	e.mu.Unlock()
-
-	switch sc.Mode {
-	case "step":
-		return e.resolveStep(k, sc, method)
-	case "time":
-		return e.resolveTime(k, sc, method, actualPath)
-	default:
-		return "", "", fmt.Errorf("unsupported mode %q", sc.Mode)
-	}
 }
 
 func (e *ScenarioResolver) TryResetByRequest(method, actualPath string) bool {
@@ -199,9 +236,7 @@ func (e *ScenarioResolver) TryResetByRequest(method, actualPath string) bool {
 
 		runtimeKey := scenarioRuntimeKey(b.ScenarioTpl, keyVal)
 
-		delete(e.stepIndex, runtimeKey)
-		delete(e.startedAt, runtimeKey)
-		delete(e.resetRules, runtimeKey)
+		e.resetKey(runtimeKey)
 
 		resetAny = true
 	}
@@ -255,19 +290,27 @@ func (e *ScenarioResolver) resolveTime(k string, sc *Scenario, method string, ac
 	}
 
 	e.mu.Lock()
+	now := e.now(k)
 	t0, ok := e.startedAt[k]
 	if !ok {
 		if len(sc.Behavior.StartOn) == 0 || matchesAny(sc.Behavior.StartOn, method, actualPath) {
-			t0 = time.Now()
+			t0 = now
 			e.startedAt[k] = t0
 		} else {
-			t0 = time.Now()
+			t0 = now
 			e.startedAt[k] = t0
 		}
 	}
-	elapsedSec := int64(time.Since(t0).Seconds())
+	elapsedSec := int64(now.Sub(t0).Seconds())
 	e.mu.Unlock()
 
+	chosen := timelineEntryAt(sc, elapsedSec)
+	return chosen.File, chosen.State, nil
+}
+
+// timelineEntryAt picks the timeline entry that is effective after elapsedSec,
+// honoring loop and repeatLast.
+func timelineEntryAt(sc *Scenario, elapsedSec int64) TimelineEntry {
 	total := sc.Timeline[len(sc.Timeline)-1].AfterSec
 	if total < 0 {
 		total = 0
@@ -289,8 +332,195 @@ func (e *ScenarioResolver) resolveTime(k string, sc *Scenario, method string, ac
 			break
 		}
 	}
+	return chosen
+}
 
-	return chosen.File, chosen.State, nil
+// SetState moves a runtime key to a named state. Keys that have not been
+// requested yet are pre-seeded. Step scenarios serve the state on the next
+// matching request; time scenarios rewind their start time to the state's afterSec.
+func (e *ScenarioResolver) SetState(sc *Scenario, swaggerTpl, keyVal, state string) error {
+	if sc == nil {
+		return fmt.Errorf("scenario is nil")
+	}
+	if strings.TrimSpace(keyVal) == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	k := scenarioRuntimeKey(swaggerTpl, keyVal)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch sc.Mode {
+	case "step":
+		for i, entry := range sc.Sequence {
+			if entry.State == state {
+				e.activate(k, sc, swaggerTpl, keyVal)
+				e.stepIndex[k] = i
+				return nil
+			}
+		}
+	case "time":
+		for _, entry := range sc.Timeline {
+			if entry.State == state {
+				e.activate(k, sc, swaggerTpl, keyVal)
+				e.startedAt[k] = e.now(k).Add(-time.Duration(entry.AfterSec) * time.Second)
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported mode %q", sc.Mode)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownState, state)
+}
+
+// Reset forgets the runtime state of one key.
+func (e *ScenarioResolver) Reset(swaggerTpl, keyVal string) bool {
+	k := scenarioRuntimeKey(swaggerTpl, keyVal)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_, ok := e.active[k]
+	e.resetKey(k)
+	return ok
+}
+
+// ResetAll forgets the runtime state and clock of every key and returns how many
+// were active. The resolver's own clock is kept.
+func (e *ScenarioResolver) ResetAll() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n := len(e.active)
+	e.stepIndex = map[string]int{}
+	e.startedAt = map[string]time.Time{}
+	e.active = map[string]activeScenario{}
+	e.resetRules = map[string][]ResetRule{}
+	e.keyClocks = map[string]keyClock{}
+	return n
+}
+
+// States lists every active runtime key with its current state, sorted by path and key.
+func (e *ScenarioResolver) States() []ScenarioState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := make([]ScenarioState, 0, len(e.active))
+	for k, a := range e.active {
+		st := ScenarioState{SwaggerPath: a.swaggerTpl, Key: a.keyVal, Mode: a.sc.Mode}
+
+		switch a.sc.Mode {
+		case "step":
+			idx := e.stepIndex[k]
+			if idx >= len(a.sc.Sequence) {
+				idx = len(a.sc.Sequence) - 1
+			}
+			if idx < 0 {
+				idx = 0
+			}
+			st.Index = &idx
+			if len(a.sc.Sequence) > 0 {
+				st.State = a.sc.Sequence[idx].State
+			}
+		case "time":
+			if t0, ok := e.startedAt[k]; ok {
+				elapsed := int64(e.now(k).Sub(t0).Seconds())
+				st.ElapsedSec = &elapsed
+				if len(a.sc.Timeline) > 0 {
+					st.State = timelineEntryAt(a.sc, elapsed).State
+				}
+			} else if len(a.sc.Timeline) > 0 {
+				st.State = a.sc.Timeline[0].State
+			}
+		}
+
+		out = append(out, st)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].SwaggerPath != out[j].SwaggerPath {
+			return out[i].SwaggerPath < out[j].SwaggerPath
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// Clock returns the virtual clock of a runtime key, creating it on first use.
+// A new key clock follows the global clock until it is changed itself.
+// Empty swaggerTpl and keyVal select the global clock.
+func (e *ScenarioResolver) Clock(swaggerTpl, keyVal string) (IVirtualClock, error) {
+	global, ok := e.clock.(IVirtualClock)
+	if !ok {
+		return nil, ErrClockNotVirtual
+	}
+	if swaggerTpl == "" && keyVal == "" {
+		return global, nil
+	}
+	if strings.TrimSpace(swaggerTpl) == "" || strings.TrimSpace(keyVal) == "" {
+		return nil, fmt.Errorf("swaggerPath and key are required for a key clock")
+	}
+
+	k := scenarioRuntimeKey(swaggerTpl, keyVal)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	kc, ok := e.keyClocks[k]
+	if !ok {
+		kc = keyClock{swaggerTpl: swaggerTpl, keyVal: keyVal, clock: NewVirtualClock(global, false)}
+		e.keyClocks[k] = kc
+	}
+	return kc.clock, nil
+}
+
+// Clocks lists the global virtual clock followed by every key clock, sorted by path and key.
+// It returns nothing when the resolver runs on the real clock.
+func (e *ScenarioResolver) Clocks() []ClockState {
+	global, ok := e.clock.(IVirtualClock)
+	if !ok {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	keys := make([]ClockState, 0, len(e.keyClocks))
+	for _, kc := range e.keyClocks {
+		keys = append(keys, ClockState{
+			SwaggerPath: kc.swaggerTpl,
+			Key:         kc.keyVal,
+			Now:         kc.clock.Now(),
+			Frozen:      kc.clock.Frozen(),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].SwaggerPath != keys[j].SwaggerPath {
+			return keys[i].SwaggerPath < keys[j].SwaggerPath
+		}
+		return keys[i].Key < keys[j].Key
+	})
+
+	return append([]ClockState{{Now: global.Now(), Frozen: global.Frozen()}}, keys...)
+}
+
+// now is the current time of a runtime key. Callers hold e.mu.
+func (e *ScenarioResolver) now(k string) time.Time {
+	if kc, ok := e.keyClocks[k]; ok {
+		return kc.clock.Now()
+	}
+	return e.clock.Now()
+}
+
+// resetKey drops all runtime state of a key, including its clock. Callers hold e.mu.
+func (e *ScenarioResolver) resetKey(k string) {
+	delete(e.stepIndex, k)
+	delete(e.startedAt, k)
+	delete(e.resetRules, k)
+	delete(e.active, k)
+	delete(e.keyClocks, k)
 }
 
 func scenarioRuntimeKey(swaggerTpl, keyVal string) string {
@@ -5,6 +5,7 @@
 package samples
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -743,6 +744,115 @@ func TestScenarioResolver_TryResetByRequest_ResetsScenarioRegisteredFromDifferen
 	}
 }
 
+func TestScenarioResolver_SetState_Step_PreseedsAndJumps(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	sc.Sequence = []ScenarioEntry{
+		{State: "requested", File: "a.json"},
+		{State: "running.3", File: "b.json"},
+		{State: "succeeded", File: "c.json"},
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+	sc.Behavior.ResetOn = []MatchRule{{Method: "DELETE", Path: "/scans/{id}"}}
+
+	if err := e.SetState(sc, "/scans/{id}/status", "abc", "running.3"); err != nil {
+		t.Fatalf("SetState: %v", err)
+	}
+
+	_, state, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/abc/status")
+	if err != nil || state != "running.3" {
+		t.Fatalf("expected pre-seeded running.3, got %q err=%v", state, err)
+	}
+
+	// pre-seeding binds resetOn rules as well
+	if !e.TryResetByRequest("DELETE", "/scans/abc") {
+		t.Fatalf("expected reset rule to be registered by SetState")
+	}
+	_, state, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/abc/status")
+	if state != "requested" {
+		t.Fatalf("expected requested after reset, got %q", state)
+	}
+
+	if err := e.SetState(sc, "/scans/{id}/status", "abc", "nope"); !errors.Is(err, ErrUnknownState) {
+		t.Fatalf("expected ErrUnknownState, got %v", err)
+	}
+	if err := e.SetState(sc, "/scans/{id}/status", " ", "requested"); err == nil {
+		t.Fatalf("expected error for empty key")
+	}
+}
+
+func TestScenarioResolver_SetState_Time_RewindsStart(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "time"}
+	sc.Key.PathParam = "id"
+	sc.Timeline = []TimelineEntry{
+		{AfterSec: 0, State: "requested", File: "a.json"},
+		{AfterSec: 60, State: "running", File: "b.json"},
+		{AfterSec: 600, State: "succeeded", File: "c.json"},
+	}
+	sc.Behavior.RepeatLast = true
+
+	if err := e.SetState(sc, "/scans/{id}/status", "abc", "running"); err != nil {
+		t.Fatalf("SetState: %v", err)
+	}
+
+	_, state, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/abc/status")
+	if err != nil || state != "running" {
+		t.Fatalf("expected running, got %q err=%v", state, err)
+	}
+
+	states := e.States()
+	if len(states) != 1 || states[0].State != "running" || states[0].ElapsedSec == nil || *states[0].ElapsedSec < 60 {
+		t.Fatalf("unexpected states: %+v", states)
+	}
+}
+
+func TestScenarioResolver_States_ResetAndResetAll(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	sc.Sequence = []ScenarioEntry{{State: "s1", File: "a.json"}, {State: "s2", File: "b.json"}}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+
+	for _, p := range []string{"/scans/b/status", "/scans/a/status", "/scans/a/status"} {
+		if _, _, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", p); err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+	}
+
+	states := e.States()
+	if len(states) != 2 {
+		t.Fatalf("expected 2 active keys, got %+v", states)
+	}
+	if states[0].Key != "a" || states[0].State != "s2" || *states[0].Index != 1 || states[0].Mode != "step" {
+		t.Fatalf("unexpected state for a: %+v", states[0])
+	}
+	if states[1].Key != "b" || states[1].State != "s2" || states[1].SwaggerPath != "/scans/{id}/status" {
+		t.Fatalf("unexpected state for b: %+v", states[1])
+	}
+
+	if !e.Reset("/scans/{id}/status", "a") {
+		t.Fatalf("expected Reset to report active key")
+	}
+	if e.Reset("/scans/{id}/status", "a") {
+		t.Fatalf("expected second Reset to report inactive key")
+	}
+	if len(e.States()) != 1 {
+		t.Fatalf("expected 1 active key after Reset")
+	}
+
+	if n := e.ResetAll(); n != 1 {
+		t.Fatalf("expected ResetAll to report 1, got %d", n)
+	}
+	if len(e.States()) != 0 {
+		t.Fatalf("expected no active keys after ResetAll")
+	}
+}
+
 func writeF(t *testing.T, path string, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+)
+
+// MaxSessions is the number of sessions kept; starting one more drops the
+// least recently used session.
+const MaxSessions = 1000
+
+// SessionStore gives every session its own scenario resolver, so step indexes,
+// start times and reset bindings of one session never affect another.
+type SessionStore struct {
+	mu          sync.Mutex
+	newResolver func() IScenarioResolver
+	sessions    map[string]*session
+	max         int
+	// uses orders sessions by their last use
+	uses uint64
+}
+
+type session struct {
+	resolver  IScenarioResolver
+	createdAt time.Time
+	lastUse   uint64
+}
+
+func NewSessionStore(newResolver func() IScenarioResolver) ISessionStore {
+	return &SessionStore{newResolver: newResolver, sessions: map[string]*session{}, max: MaxSessions}
+}
+
+func (s *SessionStore) Resolver(id string) IScenarioResolver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.getLocked(id).resolver
+}
+
+// Create starts a session and reports false when it already existed.
+// An empty id gets a generated one.
+func (s *SessionStore) Create(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id == "" {
+		id = utils.NewUUID()
+	}
+	_, existed := s.sessions[id]
+	s.getLocked(id)
+	return id, !existed
+}
+
+// Delete drops a session together with its scenario state.
+func (s *SessionStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	return ok
+}
+
+func (s *SessionStore) List() []SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]SessionInfo, 0, len(s.sessions))
+	for id, ss := range s.sessions {
+		out = append(out, SessionInfo{ID: id, CreatedAt: ss.createdAt, Active: len(ss.resolver.States())})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (s *SessionStore) getLocked(id string) *session {
+	ss, ok := s.sessions[id]
+	if !ok {
+		if len(s.sessions) >= s.max {
+			s.evictLocked()
+		}
+		ss = &session{resolver: s.newResolver(), createdAt: time.Now().UTC()}
+		s.sessions[id] = ss
+	}
+	s.uses++
+	ss.lastUse = s.uses
+	return ss
+}
+
+// evictLocked drops the least recently used session.
+func (s *SessionStore) evictLocked() {
+	var (
+		oldest string
+		use    uint64
+	)
+	for id, ss := range s.sessions {
+		if use == 0 || ss.lastUse < use {
+			oldest, use = id, ss.lastUse
+		}
+	}
+	delete(s.sessions, oldest)
+}
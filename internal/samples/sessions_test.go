@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStore_Lifecycle(t *testing.T) {
+	store := NewSessionStore(NewScenarioResolver)
+
+	id, created := store.Create("")
+	require.True(t, created)
+	require.Len(t, id, 36)
+
+	_, created = store.Create(id)
+	require.False(t, created, "second create reuses the session")
+
+	a := store.Resolver("a")
+	require.Same(t, a, store.Resolver("a"), "resolver is stable per session")
+	require.NotSame(t, a, store.Resolver(id))
+
+	list := store.List()
+	require.Len(t, list, 2)
+	require.ElementsMatch(t, []string{"a", id}, []string{list[0].ID, list[1].ID})
+
+	require.True(t, store.Delete("a"))
+	require.False(t, store.Delete("a"))
+	require.NotSame(t, a, store.Resolver("a"), "a deleted session starts over")
+}
+
+func TestSessionStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewSessionStore(NewScenarioResolver)
+	store.(*SessionStore).max = 2
+
+	a := store.Resolver("a")
+	store.Resolver("b")
+	store.Resolver("a")
+	store.Resolver("c")
+
+	ids := []string{}
+	for _, s := range store.List() {
+		ids = append(ids, s.ID)
+	}
+	require.Equal(t, []string{"a", "c"}, ids, "b was used least recently")
+	require.Same(t, a, store.Resolver("a"))
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+)
+
+// StubSourcePrefix marks Response.Source values that name a stub instead of a file.
+const StubSourcePrefix = "stub:"
+
+// StubStore keeps runtime stubs in memory. It is safe for concurrent use, so
+// parallel test suites can register their own stubs.
+type StubStore struct {
+	mu    sync.Mutex
+	seq   int64
+	stubs []*Stub
+}
+
+func NewStubStore() IStubStore {
+	return &StubStore{}
+}
+
+// Add validates and stores a stub. A missing id is generated; an existing id is replaced.
+func (s *StubStore) Add(st Stub) (Stub, error) {
+	st.Method = strings.ToUpper(strings.TrimSpace(st.Method))
+	st.Path = strings.TrimSpace(st.Path)
+	st.Session = strings.TrimSpace(st.Session)
+	switch {
+	case st.Times < 0:
+		return Stub{}, errors.New("times must not be negative")
+	case st.Response.Status != 0 && (st.Response.Status < 100 || st.Response.Status > 599):
+		return Stub{}, fmt.Errorf("invalid response status %d", st.Response.Status)
+	}
+	if st.ID == "" {
+		st.ID = utils.NewUUID()
+	}
+	st.Hits = 0
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	st.seq = s.seq
+	s.removeLocked(st.ID)
+	s.stubs = append(s.stubs, &st)
+	return st, nil
+}
+
+// List returns all stubs in match order.
+func (s *StubStore) List() []Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Stub, 0, len(s.stubs))
+	for _, st := range s.sortedLocked() {
+		out = append(out, *st)
+	}
+	return out
+}
+
+func (s *StubStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.removeLocked(id)
+}
+
+func (s *StubStore) Reset(session string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session == "" {
+		n := len(s.stubs)
+		s.stubs = nil
+		return n
+	}
+	kept := s.stubs[:0]
+	for _, st := range s.stubs {
+		if st.Session != session {
+			kept = append(kept, st)
+		}
+	}
+	n := len(s.stubs) - len(kept)
+	s.stubs = kept
+	return n
+}
+
+// Match picks the stub with the highest priority, then the most recently added,
+// among those that match the request, belong to its session or to none, and have
+// uses left.
+func (s *StubStore) Match(method, swaggerTpl, actualPath string, rc *RequestContext) (Stub, bool) {
+	method = strings.ToUpper(method)
+	data := NewTemplateData(method, swaggerTpl, actualPath, "", rc)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, st := range s.sortedLocked() {
+		if st.Times > 0 && st.Hits >= st.Times {
+			continue
+		}
+		if st.Session != "" && (rc == nil || rc.Session != st.Session) {
+			continue
+		}
+		if st.Method != "" && st.Method != method {
+			continue
+		}
+		if st.Path != "" && st.Path != swaggerTpl && st.Path != actualPath {
+			continue
+		}
+		if !st.Match.matches(data) {
+			continue
+		}
+		st.Hits++
+		return *st, true
+	}
+	return Stub{}, false
+}
+
+func (s *StubStore) sortedLocked() []*Stub {
+	out := append([]*Stub(nil), s.stubs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Priority != out[j].Priority {
+			return out[i].Priority > out[j].Priority
+		}
+		return out[i].seq > out[j].seq
+	})
+	return out
+}
+
+func (s *StubStore) removeLocked(id string) bool {
+	for i, st := range s.stubs {
+		if st.ID == id {
+			s.stubs = append(s.stubs[:i], s.stubs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// stubResponse turns a stub's envelope into a response. With templates enabled,
+// every string in its headers and body is rendered on its own, before the envelope
+// is encoded, so quotes inside actions stay unescaped.
+func (p *SampleProvider) stubResponse(st Stub, data TemplateData, clock IClock) (*Response, error) {
+	env := st.Response
+	if env.Status == 0 {
+		env.Status = http.StatusOK
+	}
+	if p.cfg.Templates {
+		render := func(s string) (string, error) {
+			b, err := RenderTemplate(StubSourcePrefix+st.ID, []byte(s), data, clock)
+			return string(b), err
+		}
+		headers := make(map[string]string, len(env.Headers))
+		for k, v := range env.Headers {
+			rv, err := render(v)
+			if err != nil {
+				return nil, fmt.Errorf("render stub %s: %w", st.ID, err)
+			}
+			headers[k] = rv
+		}
+		body, err := renderStrings(env.Body, render)
+		if err != nil {
+			return nil, fmt.Errorf("render stub %s: %w", st.ID, err)
+		}
+		env.Headers, env.Body = headers, body
+	}
+	b, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("marshal stub %s: %w", st.ID, err)
+	}
+	return parseSample(b, p.cfg.BaseDir, p.cfg.BaseDir)
+}
+
+// renderStrings returns a copy of a decoded JSON value with every string passed through render.
+func renderStrings(v any, render func(string) (string, error)) (any, error) {
+	switch t := v.(type) {
+	case string:
+		return render(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			r, err := renderStrings(e, render)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			r, err := renderStrings(e, render)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	}
+	return v, nil
+}
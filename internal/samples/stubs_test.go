@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/logger"
+)
+
+func mustStub(t *testing.T, s IStubStore, raw string) Stub {
+	t.Helper()
+	var st Stub
+	require.NoError(t, json.Unmarshal([]byte(raw), &st))
+	st, err := s.Add(st)
+	require.NoError(t, err)
+	return st
+}
+
+func TestStubStore_MatchOrderAndTimes(t *testing.T) {
+	s := NewStubStore()
+	generic := mustStub(t, s, `{"id":"generic","method":"get","path":"/scans/{id}","response":{"status":200}}`)
+	once := mustStub(t, s, `{"id":"once","method":"GET","path":"/scans/a","times":1,"response":{"status":503}}`)
+	mustStub(t, s, `{"id":"prio","priority":5,"match":{"headers":{"x-fail":"1"}},"response":{"status":500}}`)
+	require.Equal(t, "generic", generic.ID)
+	require.Equal(t, "GET", once.Method)
+
+	hit := func(method, path string, rc *RequestContext) string {
+		st, ok := s.Match(method, "/scans/{id}", path, rc)
+		if !ok {
+			return ""
+		}
+		return st.ID
+	}
+
+	require.Equal(t, "once", hit("GET", "/scans/a", nil), "newest stub wins at equal priority")
+	require.Equal(t, "generic", hit("GET", "/scans/a", nil), "times limit is used up")
+	require.Equal(t, "generic", hit("GET", "/scans/b", nil))
+	require.Equal(t, "", hit("DELETE", "/scans/b", nil))
+	require.Equal(t, "prio", hit("DELETE", "/scans/b", &RequestContext{Headers: map[string][]string{"X-Fail": {"1"}}}))
+
+	list := s.List()
+	require.Equal(t, []string{"prio", "once", "generic"}, []string{list[0].ID, list[1].ID, list[2].ID})
+	require.Equal(t, 1, list[1].Hits)
+	require.Equal(t, 2, list[2].Hits)
+
+	require.True(t, s.Delete("generic"))
+	require.False(t, s.Delete("generic"))
+	require.Equal(t, 2, s.Reset(""))
+	require.Empty(t, s.List())
+}
+
+func TestStubStore_SessionScope(t *testing.T) {
+	s := NewStubStore()
+	mustStub(t, s, `{"id":"shared","response":{"status":200}}`)
+	mustStub(t, s, `{"id":"mine","session":"x","response":{"status":503}}`)
+
+	hit := func(rc *RequestContext) string {
+		st, _ := s.Match("GET", "/scans/{id}", "/scans/a", rc)
+		return st.ID
+	}
+	require.Equal(t, "mine", hit(&RequestContext{Session: "x"}))
+	require.Equal(t, "shared", hit(&RequestContext{Session: "y"}))
+	require.Equal(t, "shared", hit(nil))
+
+	require.Equal(t, 1, s.Reset("x"))
+	require.Equal(t, "shared", hit(&RequestContext{Session: "x"}))
+	require.Len(t, s.List(), 1)
+}
+
+func TestStubStore_AddValidatesAndReplaces(t *testing.T) {
+	s := NewStubStore()
+	_, err := s.Add(Stub{Times: -1})
+	require.Error(t, err)
+	_, err = s.Add(Stub{Response: Envelope{Status: 42}})
+	require.Error(t, err)
+
+	generated, err := s.Add(Stub{})
+	require.NoError(t, err)
+	require.Len(t, generated.ID, 36)
+
+	mustStub(t, s, `{"id":"x","response":{"status":201}}`)
+	mustStub(t, s, `{"id":"x","response":{"status":202}}`)
+	require.Len(t, s.List(), 2)
+	st, ok := s.Match("GET", "/a", "/a", nil)
+	require.True(t, ok)
+	require.Equal(t, 202, st.Response.Status)
+}
+
+func TestSampleProvider_ResolveAndLoad_StubBeforeFiles(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, filepath.Join("scans", "{id}", "GET.json"), `{"from":"file"}`)
+
+	stubs := NewStubStore()
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:   baseDir,
+		Layout:    config.LayoutFolders,
+		Stubs:     stubs,
+		Templates: true,
+	}, logger.GetLogger())
+
+	mustStub(t, stubs, `{"id":"s1","path":"/scans/{id}","times":1,
+	  "response":{"status":409,"headers":{"x-stub":"1"},"body":{"id":"{{ .Path.id }}"}}}`)
+
+	resp, err := p.ResolveAndLoad("GET", "/scans/{id}", "/scans/abc", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, 409, resp.Status)
+	require.Equal(t, "1", resp.Headers["x-stub"])
+	require.JSONEq(t, `{"id":"abc"}`, string(resp.Body))
+	require.Equal(t, "stub:s1", resp.Source)
+
+	resp, err = p.ResolveAndLoad("GET", "/scans/{id}", "/scans/abc", "", nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"from":"file"}`, string(resp.Body))
+}
+
+func TestSampleProvider_ResolveAndLoad_StubBodyFile(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, filepath.Join("files", "report.xml"), `<report/>`)
+
+	stubs := NewStubStore()
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders, Stubs: stubs}, logger.GetLogger())
+
+	mustStub(t, stubs, `{"id":"xml","path":"/reports/xml","response":{"bodyFile":"files/report.xml"}}`)
+	mustStub(t, stubs, `{"id":"escape","path":"/reports/escape","response":{"bodyFile":"../secret.txt"}}`)
+
+	resp, err := p.ResolveAndLoad("GET", "/reports/xml", "/reports/xml", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "application/xml", resp.Headers["content-type"])
+	require.Equal(t, `<report/>`, string(resp.Body))
+
+	_, err = p.ResolveAndLoad("GET", "/reports/escape", "/reports/escape", "", nil)
+	require.ErrorIs(t, err, ErrBodyFile)
+}
+
+func TestSampleProvider_ResolveAndLoad_StubTemplateWithQuotedArguments(t *testing.T) {
+	stubs := NewStubStore()
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:   t.TempDir(),
+		Layout:    config.LayoutFolders,
+		Stubs:     stubs,
+		Templates: true,
+	}, logger.GetLogger())
+
+	mustStub(t, stubs, `{"id":"q","path":"/scans/{id}","response":{
+	  "headers":{"x-filter":"{{ index .Query \"filter\" }}"},
+	  "body":{"id":"{{ default \"none\" .Path.id }}","tags":["{{ index .Query \"filter\" }}", 1]}}}`)
+
+	rc := &RequestContext{Query: map[string][]string{"filter": {"running"}}}
+	resp, err := p.ResolveAndLoad("GET", "/scans/{id}", "/scans/abc", "", rc)
+	require.NoError(t, err)
+	require.Equal(t, "running", resp.Headers["x-filter"])
+	require.JSONEq(t, `{"id":"abc","tags":["running",1]}`, string(resp.Body))
+
+	// the registered stub keeps its templates for the next request
+	resp, err = p.ResolveAndLoad("GET", "/scans/{id}", "/scans/xyz", "", nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"xyz","tags":["",1]}`, string(resp.Body))
+}
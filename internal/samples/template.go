@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+)
+
+var ErrTemplate = errors.New("sample template failed")
+
+// TemplateEnvPrefix limits which environment variables templates can read: runtime
+// stubs are templated too, and anyone who reaches the admin API can register one.
+const TemplateEnvPrefix = "EMULATOR_"
+
+// NewTemplateData collects what a sample template can see about the current request.
+// Path params are taken from actualPath using swaggerTpl; rc may be nil.
+func NewTemplateData(method, swaggerTpl, actualPath, state string, rc *RequestContext) TemplateData {
+	data := TemplateData{
+		Method:  strings.ToUpper(method),
+		Path:    map[string]string{},
+		Query:   map[string]string{},
+		Headers: map[string]string{},
+		Body:    map[string]any{},
+		State:   state,
+	}
+
+	if rc != nil && rc.PathParams != nil {
+		for k, v := range rc.PathParams {
+			data.Path[k] = v
+		}
+	} else {
+		for _, p := range strings.Split(strings.Trim(swaggerTpl, "/"), "/") {
+			if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+				name := strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}")
+				if v, ok := extractPathParam(swaggerTpl, actualPath, name); ok {
+					data.Path[name] = v
+				}
+			}
+		}
+	}
+
+	if rc == nil {
+		return data
+	}
+	for k, v := range rc.Query {
+		if len(v) > 0 {
+			data.Query[k] = v[0]
+		}
+	}
+	for k, v := range rc.Headers {
+		if len(v) > 0 {
+			data.Headers[strings.ToLower(k)] = v[0]
+		}
+	}
+	if len(bytes.TrimSpace(rc.Body)) > 0 {
+		var body any
+		if err := json.Unmarshal(rc.Body, &body); err == nil {
+			data.Body = body
+		}
+	}
+	return data
+}
+
+// RenderTemplate executes a sample file as a text/template. The result is parsed
+// like any other sample, so envelope status, headers and body can all be templated.
+func RenderTemplate(name string, raw []byte, data TemplateData, clock IClock) ([]byte, error) {
+	if !bytes.Contains(raw, []byte("{{")) {
+		return raw, nil
+	}
+	if clock == nil {
+		clock = RealClock{}
+	}
+
+	tpl, err := template.New(name).Option("missingkey=zero").Funcs(templateFuncs(clock)).Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTemplate, err)
+	}
+
+	var out bytes.Buffer
+	if err := tpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTemplate, err)
+	}
+	return out.Bytes(), nil
+}
+
+func templateFuncs(clock IClock) template.FuncMap {
+	return template.FuncMap{
+		// now formats the current time, RFC 3339 unless a Go layout is given
+		"now": func(layout ...string) string {
+			if len(layout) > 0 {
+				return clock.Now().UTC().Format(layout[0])
+			}
+			return clock.Now().UTC().Format(time.RFC3339)
+		},
+		"unix": func() int64 { return clock.Now().Unix() },
+		"uuid": utils.NewUUID,
+		// randInt returns a random integer in [min, max]
+		"randInt": func(lo, hi int) (int, error) {
+			if hi < lo {
+				return 0, fmt.Errorf("randInt: max %d < min %d", hi, lo)
+			}
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(hi-lo)+1))
+			if err != nil {
+				return 0, err
+			}
+			return lo + int(n.Int64()), nil
+		},
+		// env reads an EMULATOR_* environment variable
+		"env": func(name string) (string, error) {
+			if !strings.HasPrefix(name, TemplateEnvPrefix) {
+				return "", fmt.Errorf("env: only %s* variables can be read, not %q", TemplateEnvPrefix, name)
+			}
+			return os.Getenv(name), nil
+		},
+		// json encodes a value, so strings land in the output properly quoted and escaped
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		// default returns def when v is missing or empty
+		"default": func(def, v any) any {
+			if v == nil {
+				return def
+			}
+			if s, ok := v.(string); ok && s == "" {
+				return def
+			}
+			return v
+		},
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/logger"
+)
+
+func TestNewTemplateData_CollectsRequest(t *testing.T) {
+	rc := &RequestContext{
+		Query:   url.Values{"details": {"true", "false"}},
+		Headers: http.Header{"X-Request-Id": {"r-1"}},
+		Body:    []byte(`{"target":{"hosts":["10.0.0.1"]}}`),
+	}
+
+	data := NewTemplateData("get", "/scans/{id}/results/{rid}", "/scans/abc/results/7", "running", rc)
+
+	require.Equal(t, "GET", data.Method)
+	require.Equal(t, map[string]string{"id": "abc", "rid": "7"}, data.Path)
+	require.Equal(t, "true", data.Query["details"])
+	require.Equal(t, "r-1", data.Headers["x-request-id"])
+	require.Equal(t, "running", data.State)
+	require.Equal(t, map[string]any{"target": map[string]any{"hosts": []any{"10.0.0.1"}}}, data.Body)
+}
+
+func TestRenderTemplate_Helpers(t *testing.T) {
+	t.Setenv("EMULATOR_REGION", "eu")
+	clock := &fakeClock{t: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)}
+	data := NewTemplateData("POST", "/scans/{id}", "/scans/abc", "", &RequestContext{
+		Body: []byte(`{"name":"a \"quoted\" name"}`),
+	})
+
+	out, err := RenderTemplate("t", []byte(`{
+	  "id": {{ json .Path.id }},
+	  "name": {{ json .Body.name }},
+	  "missing": {{ json (default "n/a" .Body.nope) }},
+	  "region": "{{ env "EMULATOR_REGION" }}",
+	  "at": "{{ now }}",
+	  "day": "{{ now "2006-01-02" }}",
+	  "unix": {{ unix }},
+	  "uuid": "{{ uuid }}",
+	  "n": {{ randInt 3 3 }}
+	}`), data, clock)
+	require.NoError(t, err)
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(out, &m), string(out))
+	require.Equal(t, "abc", m["id"])
+	require.Equal(t, `a "quoted" name`, m["name"])
+	require.Equal(t, "n/a", m["missing"])
+	require.Equal(t, "eu", m["region"])
+	require.Equal(t, "2026-03-04T05:06:07Z", m["at"])
+	require.Equal(t, "2026-03-04", m["day"])
+	require.Equal(t, float64(clock.t.Unix()), m["unix"])
+	require.Len(t, m["uuid"], 36)
+	require.Equal(t, float64(3), m["n"])
+}
+
+func TestRenderTemplate_EnvOnlyReadsEmulatorVariables(t *testing.T) {
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	out, err := RenderTemplate("stub:s1", []byte(`{"leak":"{{ env "AWS_SECRET_ACCESS_KEY" }}"}`), NewTemplateData("GET", "/", "/", "", nil), nil)
+	require.ErrorIs(t, err, ErrTemplate)
+	require.NotContains(t, string(out), "secret")
+}
+
+func TestRenderTemplate_PlainBytesUntouched(t *testing.T) {
+	raw := []byte(`{"id":"fixed"}`)
+	out, err := RenderTemplate("t", raw, TemplateData{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, raw, out)
+}
+
+func TestRenderTemplate_Errors(t *testing.T) {
+	_, err := RenderTemplate("t", []byte(`{{ .Path.id `), TemplateData{}, nil)
+	require.ErrorIs(t, err, ErrTemplate)
+
+	_, err = RenderTemplate("t", []byte(`{{ randInt 5 1 }}`), TemplateData{}, nil)
+	require.ErrorIs(t, err, ErrTemplate)
+}
+
+func TestSampleProvider_ResolveAndLoad_Templates(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, filepath.Join("scans", "{id}", "status", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam":"id"},
+	  "sequence": [{"state":"running","file":"GET.running.json"}],
+	  "behavior": {"repeatLast": true}
+	}`)
+	writeFile(t, baseDir, filepath.Join("scans", "{id}", "status", "GET.running.json"),
+		`{"headers":{"x-scan":"{{ .Path.id }}"},"body":{"id":{{ json .Path.id }},"state":{{ json .State }}}}`)
+
+	newProvider := func(templates bool) ISampleProvider {
+		return NewSampleProvider(ProviderConfig{
+			BaseDir:          baseDir,
+			Layout:           config.LayoutFolders,
+			ScenarioEnabled:  true,
+			ScenarioFilename: "scenario.json",
+			ScenarioResolver: NewScenarioResolver(),
+			Templates:        templates,
+		}, logger.GetLogger())
+	}
+
+	resp, err := newProvider(true).ResolveAndLoad("GET", "/scans/{id}/status", "/scans/abc/status", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"abc","state":"running"}`, string(resp.Body))
+	require.Equal(t, "abc", resp.Headers["x-scan"])
+	require.Equal(t, "running", resp.State)
+
+	// without TEMPLATES_ENABLED the file is not valid JSON and is served raw
+	resp, err = newProvider(false).ResolveAndLoad("GET", "/scans/{id}/status", "/scans/abc/status", "", nil)
+	require.NoError(t, err)
+	require.Contains(t, string(resp.Body), "{{ json .Path.id }}")
+}
+
+func TestRenderTemplate_MissingBodyField(t *testing.T) {
+	out, err := RenderTemplate("t", []byte(`{{ json (default "unnamed" .Body.name) }}`),
+		NewTemplateData("GET", "/x", "/x", "", nil), nil)
+	require.NoError(t, err)
+	require.Equal(t, `"unnamed"`, string(out))
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VariantsFilename is the file in an endpoint folder that defines request-matched variants.
+const VariantsFilename = "variants.json"
+
+func (m *Matcher) UnmarshalJSON(b []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err == nil && len(obj) > 0 && onlyMatcherKeys(obj) {
+		type plain Matcher
+		var p plain
+		if err := json.Unmarshal(b, &p); err != nil {
+			return err
+		}
+		*m = Matcher(p)
+		if m.Regex != "" {
+			re, err := regexp.Compile(m.Regex)
+			if err != nil {
+				return fmt.Errorf("invalid regex %q: %w", m.Regex, err)
+			}
+			m.re = re
+		}
+		return nil
+	}
+
+	// anything else is a value to compare with
+	return json.Unmarshal(b, &m.Equals)
+}
+
+func onlyMatcherKeys(obj map[string]json.RawMessage) bool {
+	for k := range obj {
+		if k != "equals" && k != "regex" && k != "present" {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadVariants parses and checks a variants.json file.
+func LoadVariants(path string) (*VariantSet, error) {
+	b, err := os.ReadFile(path) // #nosec G304 -- endpoint folder below the samples dir
+	if err != nil {
+		return nil, err
+	}
+
+	var vs VariantSet
+	if err := json.Unmarshal(b, &vs); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	if vs.Version != 1 {
+		return nil, fmt.Errorf("unsupported variants version: %d", vs.Version)
+	}
+
+	for i := range vs.Variants {
+		v := &vs.Variants[i]
+		v.Method = strings.ToUpper(strings.TrimSpace(v.Method))
+		if v.Method == "" {
+			return nil, fmt.Errorf("variant %d: method is required", i)
+		}
+		if strings.TrimSpace(v.File) == "" {
+			return nil, fmt.Errorf("variant %d: file is required", i)
+		}
+	}
+	return &vs, nil
+}
+
+// Select returns the best variant for a request, or nil when none matches.
+func (vs *VariantSet) Select(method string, req TemplateData) *Variant {
+	method = strings.ToUpper(method)
+
+	var best *Variant
+	bestSpecificity := -1
+	for i := range vs.Variants {
+		v := &vs.Variants[i]
+		if v.Method != method || !v.Match.matches(req) {
+			continue
+		}
+
+		specificity := v.Match.count()
+		if best == nil || v.Priority > best.Priority ||
+			(v.Priority == best.Priority && specificity > bestSpecificity) {
+			best = v
+			bestSpecificity = specificity
+		}
+	}
+	return best
+}
+
+func (vm VariantMatch) count() int {
+	return len(vm.Query) + len(vm.Headers) + len(vm.Path) + len(vm.Body)
+}
+
+func (vm VariantMatch) matches(req TemplateData) bool {
+	return len(vm.Mismatches(req)) == 0
+}
+
+// Mismatches describes every predicate the request does not satisfy, in a stable order.
+func (vm VariantMatch) Mismatches(req TemplateData) []string {
+	var out []string
+	strParams := func(part string, preds map[string]Matcher, values map[string]string, fold bool) {
+		for _, name := range sortedKeys(preds) {
+			key := name
+			if fold {
+				key = strings.ToLower(name)
+			}
+			v, ok := values[key]
+			var val any
+			if ok {
+				val = v
+			}
+			if !preds[name].matches(val, ok) {
+				out = append(out, describeMismatch(part+" "+name, preds[name], val, ok))
+			}
+		}
+	}
+
+	strParams("query", vm.Query, req.Query, false)
+	strParams("header", vm.Headers, req.Headers, true)
+	strParams("path", vm.Path, req.Path, false)
+
+	for _, sel := range sortedKeys(vm.Body) {
+		v, ok := lookupJSON(req.Body, sel)
+		if !vm.Body[sel].matches(v, ok) {
+			out = append(out, describeMismatch("body "+sel, vm.Body[sel], v, ok))
+		}
+	}
+	return out
+}
+
+func describeMismatch(what string, m Matcher, v any, present bool) string {
+	var want []string
+	if m.Present != nil {
+		want = append(want, fmt.Sprintf("present=%v", *m.Present))
+	}
+	if m.Equals != nil {
+		want = append(want, "equals "+stringForm(m.Equals))
+	}
+	if m.Regex != "" {
+		want = append(want, "regex "+m.Regex)
+	}
+	got := "missing"
+	if present {
+		got = stringForm(v)
+	}
+	return fmt.Sprintf("%s: want %s, got %s", what, strings.Join(want, " and "), got)
+}
+
+func sortedKeys(m map[string]Matcher) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (m Matcher) matches(v any, present bool) bool {
+	if m.Present != nil && *m.Present != present {
+		return false
+	}
+	if !present {
+		return m.Present != nil && m.Equals == nil && m.re == nil
+	}
+
+	if m.re != nil && !m.re.MatchString(stringForm(v)) {
+		return false
+	}
+	if m.Equals != nil {
+		if s, ok := v.(string); ok {
+			// request strings (query, headers, path) compare with any JSON scalar
+			return s == stringForm(m.Equals)
+		}
+		return reflect.DeepEqual(v, m.Equals)
+	}
+	return true
+}
+
+func stringForm(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// lookupJSON resolves a selector like $.target.hosts[0].name in a decoded JSON value.
+func lookupJSON(v any, sel string) (any, bool) {
+	sel = strings.TrimPrefix(strings.TrimSpace(sel), "$")
+	for sel != "" {
+		switch {
+		case strings.HasPrefix(sel, "."):
+			sel = sel[1:]
+			end := strings.IndexAny(sel, ".[")
+			if end < 0 {
+				end = len(sel)
+			}
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if v, ok = obj[sel[:end]]; !ok {
+				return nil, false
+			}
+			sel = sel[end:]
+		case strings.HasPrefix(sel, "["):
+			end := strings.Index(sel, "]")
+			if end < 0 {
+				return nil, false
+			}
+			idx, err := strconv.Atoi(sel[1:end])
+			arr, ok := v.([]any)
+			if err != nil || !ok || idx < 0 || idx >= len(arr) {
+				return nil, false
+			}
+			v = arr[idx]
+			sel = sel[end+1:]
+		default:
+			// allow selectors without the leading "$."
+			sel = "." + sel
+		}
+	}
+	return v, true
+}
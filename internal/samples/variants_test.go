@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/logger"
+)
+
+func TestMatcher_UnmarshalForms(t *testing.T) {
+	var m map[string]Matcher
+	require.NoError(t, json.Unmarshal([]byte(`{
+	  "a": "stop",
+	  "b": 5,
+	  "c": {"regex": "^10\\."},
+	  "d": {"present": false},
+	  "e": {"hosts": ["x"]}
+	}`), &m))
+
+	require.Equal(t, "stop", m["a"].Equals)
+	require.Equal(t, float64(5), m["b"].Equals)
+	require.NotNil(t, m["c"].re)
+	require.False(t, *m["d"].Present)
+	require.Equal(t, map[string]any{"hosts": []any{"x"}}, m["e"].Equals, "objects with other keys are values")
+
+	require.Error(t, json.Unmarshal([]byte(`{"x":{"regex":"("}}`), &m))
+}
+
+func TestLookupJSON(t *testing.T) {
+	var body any
+	require.NoError(t, json.Unmarshal([]byte(`{"target":{"hosts":["10.0.0.1",{"name":"h"}]},"action":"stop"}`), &body))
+
+	cases := []struct {
+		sel  string
+		want any
+		ok   bool
+	}{
+		{"$.action", "stop", true},
+		{"action", "stop", true},
+		{"$.target.hosts[0]", "10.0.0.1", true},
+		{"$.target.hosts[1].name", "h", true},
+		{"$.target.hosts[2]", nil, false},
+		{"$.target.ports", nil, false},
+		{"$.action.x", nil, false},
+	}
+	for _, tc := range cases {
+		got, ok := lookupJSON(body, tc.sel)
+		require.Equal(t, tc.ok, ok, tc.sel)
+		require.Equal(t, tc.want, got, tc.sel)
+	}
+}
+
+func TestVariantSet_Select(t *testing.T) {
+	var vs VariantSet
+	require.NoError(t, json.Unmarshal([]byte(`{
+	  "version": 1,
+	  "variants": [
+		{"name":"stop","method":"POST","match":{"body":{"$.action":"stop"}},"file":"POST.stop.json"},
+		{"name":"start","method":"post","match":{"body":{"$.action":"start"}},"file":"POST.start.json"},
+		{"name":"stop-admin","method":"POST","match":{"body":{"$.action":"stop"},"headers":{"X-Role":"admin"}},"file":"POST.stop-admin.json"},
+		{"name":"forced","method":"POST","priority":10,"match":{"query":{"force":{"regex":"^(1|true)$"}}},"file":"POST.forced.json"},
+		{"name":"no-body","method":"POST","match":{"body":{"$.action":{"present":false}}},"file":"POST.empty.json"},
+		{"name":"abc","method":"GET","match":{"path":{"id":"abc"}},"file":"GET.abc.json"}
+	  ]
+	}`), &vs))
+	for i := range vs.Variants {
+		vs.Variants[i].Method = "POST"
+	}
+	vs.Variants[5].Method = "GET"
+
+	pick := func(method, path string, rc *RequestContext) string {
+		v := vs.Select(method, NewTemplateData(method, "/scans/{id}", path, "", rc))
+		if v == nil {
+			return ""
+		}
+		return v.Name
+	}
+	body := func(s string) *RequestContext { return &RequestContext{Body: []byte(s)} }
+
+	require.Equal(t, "stop", pick("POST", "/scans/1", body(`{"action":"stop"}`)))
+	require.Equal(t, "start", pick("POST", "/scans/1", body(`{"action":"start"}`)))
+	require.Equal(t, "", pick("POST", "/scans/1", body(`{"action":"pause"}`)))
+	require.Equal(t, "no-body", pick("POST", "/scans/1", nil))
+
+	// more predicates win over fewer at equal priority
+	rc := body(`{"action":"stop"}`)
+	rc.Headers = http.Header{"X-Role": {"admin"}}
+	require.Equal(t, "stop-admin", pick("POST", "/scans/1", rc))
+
+	// higher priority wins regardless of specificity
+	rc.Query = url.Values{"force": {"true"}}
+	require.Equal(t, "forced", pick("POST", "/scans/1", rc))
+
+	require.Equal(t, "abc", pick("GET", "/scans/abc", nil))
+	require.Equal(t, "", pick("GET", "/scans/def", nil))
+}
+
+func TestSampleProvider_ResolveAndLoad_Variants(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join("scans", "{id}")
+	writeFile(t, baseDir, filepath.Join(dir, "variants.json"), `{
+	  "version": 1,
+	  "variants": [
+		{"method":"POST","match":{"body":{"$.action":"stop"}},"file":"POST.stop.json"},
+		{"method":"POST","match":{"body":{"$.action":"start"}},"file":"POST.start.json"}
+	  ]
+	}`)
+	writeFile(t, baseDir, filepath.Join(dir, "POST.stop.json"), `{"status":200,"body":{"stopped":true}}`)
+	writeFile(t, baseDir, filepath.Join(dir, "POST.start.json"), `{"status":202,"body":{"started":true}}`)
+	writeFile(t, baseDir, filepath.Join(dir, "POST.json"), `{"status":400,"body":{"error":"unknown action"}}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutAuto}, logger.GetLogger())
+
+	load := func(body string) *Response {
+		resp, err := p.ResolveAndLoad("POST", "/scans/{id}", "/scans/abc", "POST__scans_{id}.json", &RequestContext{Body: []byte(body)})
+		require.NoError(t, err)
+		return resp
+	}
+
+	require.Equal(t, 200, load(`{"action":"stop"}`).Status)
+	require.Equal(t, 202, load(`{"action":"start"}`).Status)
+	require.Equal(t, 400, load(`{"action":"pause"}`).Status, "plain sample is the fallback")
+
+	writeFile(t, baseDir, filepath.Join(dir, "variants.json"), `{"version":1,"variants":[{"method":"POST","match":{},"file":"POST.gone.json"}]}`)
+	_, err := p.ResolveAndLoad("POST", "/scans/{id}", "/scans/abc", "", nil)
+	require.ErrorContains(t, err, "variant file not found")
+
+	writeFile(t, baseDir, filepath.Join(dir, "variants.json"), `{"version":1,"variants":[{"match":{},"file":"POST.stop.json"}]}`)
+	_, err = p.ResolveAndLoad("POST", "/scans/{id}", "/scans/abc", "", nil)
+	require.ErrorContains(t, err, "method is required")
+}
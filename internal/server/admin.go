@@ -0,0 +1,363 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminPrefix is the reserved path namespace for emulator control endpoints.
+const AdminPrefix = "/__emulator"
+
+// scenarioTarget selects one scenario key, either by an actual request path
+// (e.g. /scans/abc/status) or by swagger path template and key value.
+type scenarioTarget struct {
+	Path        string `json:"path,omitempty"`
+	SwaggerPath string `json:"swaggerPath,omitempty"`
+	Key         string `json:"key,omitempty"`
+	State       string `json:"state,omitempty"`
+}
+
+// clockRequest controls the global clock, or a key clock when a target is set.
+type clockRequest struct {
+	scenarioTarget
+	Seconds *float64 `json:"seconds,omitempty"`
+	Time    string   `json:"time,omitempty"`
+}
+
+func isAdminPath(path string) bool {
+	return path == AdminPrefix || strings.HasPrefix(path, AdminPrefix+"/")
+}
+
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+
+	switch {
+	case path == AdminPrefix+"/scenarios" && r.Method == http.MethodGet:
+		s.adminListScenarios(w, r)
+	case path == AdminPrefix+"/scenarios/state" && r.Method == http.MethodPost:
+		s.adminSetScenarioState(w, r)
+	case path == AdminPrefix+"/scenarios/reset" && r.Method == http.MethodPost:
+		s.adminResetScenarios(w, r)
+	case path == AdminPrefix+"/sessions" && r.Method == http.MethodGet:
+		s.adminListSessions(w)
+	case path == AdminPrefix+"/sessions" && r.Method == http.MethodPost:
+		s.adminCreateSession(w, r)
+	case strings.HasPrefix(path, AdminPrefix+"/sessions/") && r.Method == http.MethodDelete:
+		s.adminDeleteSession(w, strings.TrimPrefix(path, AdminPrefix+"/sessions/"))
+	case path == AdminPrefix+"/reload" && r.Method == http.MethodPost:
+		s.adminReload(w)
+	case path == AdminPrefix+"/resources" && r.Method == http.MethodGet:
+		s.adminListResources(w)
+	case path == AdminPrefix+"/resources/reset" && r.Method == http.MethodPost:
+		s.adminResetResources(w)
+	case path == AdminPrefix+"/requests" && r.Method == http.MethodGet:
+		s.adminListRequests(w, r)
+	case path == AdminPrefix+"/requests/reset" && r.Method == http.MethodPost:
+		s.adminResetRequests(w)
+	case path == AdminPrefix+"/requests/verify" && r.Method == http.MethodPost:
+		s.adminVerifyRequests(w, r)
+	case path == AdminPrefix+"/stubs" && r.Method == http.MethodGet:
+		utils.WriteJSON(w, 200, map[string]any{"stubs": s.stubs.List()})
+	case path == AdminPrefix+"/stubs" && r.Method == http.MethodPost:
+		s.adminCreateStub(w, r)
+	case path == AdminPrefix+"/stubs/reset" && r.Method == http.MethodPost:
+		s.adminResetStubs(w, r)
+	case strings.HasPrefix(path, AdminPrefix+"/stubs/") && r.Method == http.MethodDelete:
+		s.adminDeleteStub(w, strings.TrimPrefix(path, AdminPrefix+"/stubs/"))
+	case path == AdminPrefix+"/clock" && r.Method == http.MethodGet:
+		s.adminListClocks(w, r)
+	case strings.HasPrefix(path, AdminPrefix+"/clock/") && r.Method == http.MethodPost:
+		s.adminControlClock(w, r, strings.TrimPrefix(path, AdminPrefix+"/clock/"))
+	default:
+		utils.WriteJSON(w, 404, map[string]any{
+			"error":  "Unknown admin endpoint",
+			"method": r.Method,
+			"path":   r.URL.Path,
+		})
+	}
+}
+
+func (s *Server) adminListScenarios(w http.ResponseWriter, r *http.Request) {
+	if !s.requireScenarios(w) {
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"scenarios": s.resolverFor(r).States()})
+}
+
+func (s *Server) adminSetScenarioState(w http.ResponseWriter, r *http.Request) {
+	if !s.requireScenarios(w) {
+		return
+	}
+
+	var t scenarioTarget
+	if err := decodeAdminBody(r, &t); err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+	if strings.TrimSpace(t.State) == "" {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": "state is required"})
+		return
+	}
+
+	sc, tpl, key, err := s.resolveScenarioTarget(t)
+	if err != nil {
+		utils.WriteJSON(w, 404, map[string]any{"error": "Scenario not found", "details": err.Error()})
+		return
+	}
+
+	if err := s.resolverFor(r).SetState(sc, tpl, key, t.State); err != nil {
+		status := 400
+		if errors.Is(err, samples.ErrUnknownState) {
+			status = 404
+		}
+		utils.WriteJSON(w, status, map[string]any{"error": "Cannot set scenario state", "details": err.Error()})
+		return
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"swaggerPath": tpl,
+		"key":         key,
+		"state":       t.State,
+	}).Info("scenario state set via admin API")
+	utils.WriteJSON(w, 200, map[string]any{"swaggerPath": tpl, "key": key, "state": t.State})
+}
+
+func (s *Server) adminResetScenarios(w http.ResponseWriter, r *http.Request) {
+	if !s.requireScenarios(w) {
+		return
+	}
+
+	var t scenarioTarget
+	if err := decodeAdminBody(r, &t); err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+
+	if t.Path == "" && t.SwaggerPath == "" && t.Key == "" {
+		n := s.resolverFor(r).ResetAll()
+		utils.WriteJSON(w, 200, map[string]any{"reset": n})
+		return
+	}
+
+	_, tpl, key, err := s.resolveScenarioTarget(t)
+	if err != nil {
+		utils.WriteJSON(w, 404, map[string]any{"error": "Scenario not found", "details": err.Error()})
+		return
+	}
+
+	n := 0
+	if s.resolverFor(r).Reset(tpl, key) {
+		n = 1
+	}
+	utils.WriteJSON(w, 200, map[string]any{"reset": n, "swaggerPath": tpl, "key": key})
+}
+
+func (s *Server) adminListClocks(w http.ResponseWriter, r *http.Request) {
+	if !s.requireVirtualClock(w) {
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"clocks": s.resolverFor(r).Clocks()})
+}
+
+// adminControlClock applies freeze, resume, advance or set to the global clock,
+// or to the clock of one scenario key when the body selects a target.
+func (s *Server) adminControlClock(w http.ResponseWriter, r *http.Request, action string) {
+	if !s.requireVirtualClock(w) {
+		return
+	}
+
+	var req clockRequest
+	if err := decodeAdminBody(r, &req); err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+
+	var tpl, key string
+	if req.Path != "" || req.SwaggerPath != "" || req.Key != "" {
+		var err error
+		if _, tpl, key, err = s.resolveScenarioTarget(req.scenarioTarget); err != nil {
+			utils.WriteJSON(w, 404, map[string]any{"error": "Scenario not found", "details": err.Error()})
+			return
+		}
+	}
+
+	clock, err := s.resolverFor(r).Clock(tpl, key)
+	if err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+
+	switch action {
+	case "freeze":
+		clock.Freeze()
+	case "resume":
+		clock.Resume()
+	case "advance":
+		if req.Seconds == nil || *req.Seconds < 0 {
+			utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": "seconds must be a non-negative number"})
+			return
+		}
+		clock.Advance(time.Duration(*req.Seconds * float64(time.Second)))
+	case "set":
+		t, err := time.Parse(time.RFC3339, req.Time)
+		if err != nil {
+			utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": fmt.Sprintf("time must be RFC3339: %v", err)})
+			return
+		}
+		clock.Set(t)
+	default:
+		utils.WriteJSON(w, 404, map[string]any{
+			"error":  "Unknown admin endpoint",
+			"method": r.Method,
+			"path":   r.URL.Path,
+		})
+		return
+	}
+
+	st := samples.ClockState{SwaggerPath: tpl, Key: key, Now: clock.Now(), Frozen: clock.Frozen()}
+	s.log.WithFields(logrus.Fields{
+		"action":      action,
+		"swaggerPath": tpl,
+		"key":         key,
+		"now":         st.Now.Format(time.RFC3339),
+		"frozen":      st.Frozen,
+	}).Info("scenario clock changed via admin API")
+	utils.WriteJSON(w, 200, st)
+}
+
+func (s *Server) requireVirtualClock(w http.ResponseWriter) bool {
+	if !s.requireScenarios(w) {
+		return false
+	}
+	if _, err := s.scenario.Clock("", ""); err == nil {
+		return true
+	}
+	utils.WriteJSON(w, 409, map[string]any{
+		"error": "Virtual clock is disabled",
+		"hint":  "Set SCENARIO_CLOCK=virtual",
+	})
+	return false
+}
+
+func (s *Server) adminListResources(w http.ResponseWriter) {
+	if !s.requireResources(w) {
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"collections": s.resources.Counts()})
+}
+
+func (s *Server) adminResetResources(w http.ResponseWriter) {
+	if !s.requireResources(w) {
+		return
+	}
+	n := s.resources.Reset()
+	s.log.WithFields(logrus.Fields{"reset": n}).Info("resource store reset via admin API")
+	utils.WriteJSON(w, 200, map[string]any{"reset": n})
+}
+
+func (s *Server) requireResources(w http.ResponseWriter) bool {
+	if s.resources != nil {
+		return true
+	}
+	utils.WriteJSON(w, 409, map[string]any{
+		"error": "Resources are disabled",
+		"hint":  "Set RESOURCES_ENABLED=true",
+	})
+	return false
+}
+
+func (s *Server) requireScenarios(w http.ResponseWriter) bool {
+	if s.scenario != nil {
+		return true
+	}
+	utils.WriteJSON(w, 409, map[string]any{
+		"error": "Scenarios are disabled",
+		"hint":  "Set SCENARIO_ENABLED=true",
+	})
+	return false
+}
+
+// resolveScenarioTarget finds the scenario, its swagger template and the key value of a target.
+func (s *Server) resolveScenarioTarget(t scenarioTarget) (*samples.Scenario, string, string, error) {
+	if t.Path != "" {
+		rp := s.current().routerProvider
+		path, _ := rp.StripBasePath(t.Path)
+		// the path is routed like a request, GET first as scenarios usually serve reads
+		for _, method := range append([]string{http.MethodGet}, rp.AllowedMethods(path)...) {
+			rt := s.findRoute(rp, method, path)
+			if rt == nil {
+				continue
+			}
+			sc, err := s.scenarioFor(rt.Swagger)
+			if err != nil {
+				continue
+			}
+			key := rt.Params[sc.Key.PathParam]
+			if key == "" {
+				return nil, "", "", fmt.Errorf("path %s has no value for key param %q", t.Path, sc.Key.PathParam)
+			}
+			return sc, rt.Swagger, key, nil
+		}
+		return nil, "", "", fmt.Errorf("no scenario serves path %s", t.Path)
+	}
+
+	if t.SwaggerPath == "" || t.Key == "" {
+		return nil, "", "", fmt.Errorf("either path or swaggerPath and key are required")
+	}
+	sc, err := s.scenarioFor(t.SwaggerPath)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return sc, t.SwaggerPath, t.Key, nil
+}
+
+// scenarioFor returns the preloaded scenario of a swagger template, or loads it from disk.
+// Only templates of the spec's routes are looked up.
+func (s *Server) scenarioFor(swaggerTpl string) (*samples.Scenario, error) {
+	st := s.current()
+	if sc, ok := st.scenarios[swaggerTpl]; ok {
+		return sc, nil
+	}
+	if _, ok := routeOperations(st.routerProvider)[swaggerTpl]; !ok {
+		return nil, fmt.Errorf("no route %s in the spec", swaggerTpl)
+	}
+
+	p := samples.ScenarioPathForSwagger(s.cfg.SamplesDir, swaggerTpl, config.Envs.Scenario.Filename)
+	if !utils.FileExists(p) {
+		return nil, fmt.Errorf("no %s for %s", filepath.Base(p), swaggerTpl)
+	}
+	return samples.LoadScenario(p)
+}
+
+// decodeAdminBody decodes an optional JSON request body.
+func decodeAdminBody(r *http.Request, v any) error {
+	if r.Body == nil {
+		return nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(b)) == "" {
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
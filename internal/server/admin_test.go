@@ -0,0 +1,325 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
+
+func newScenarioServer(t *testing.T) *Server {
+	t.Helper()
+
+	prev := config.Envs.Scenario
+	t.Cleanup(func() { config.Envs.Scenario = prev })
+	config.Envs.Scenario = config.ScenarioConfig{Enabled: true, Filename: "scenario.json", Validation: config.ScenarioValidationFail}
+
+	dir := t.TempDir()
+	spec := strings.Replace(minimalSpec(), `"paths":{`, `"paths":{
+		"/items/latest":{"get":{"responses":{"200":{"description":"ok"}}}},`, 1)
+	specPath := writeFile(t, dir, "spec.json", spec)
+
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam":"id"},
+	  "sequence": [
+		{"state":"queued","file":"GET.queued.json"},
+		{"state":"running","file":"GET.running.json"},
+		{"state":"done","file":"GET.done.json"}
+	  ],
+	  "behavior": {"advanceOn":[{"method":"GET"}], "repeatLast": true}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.queued.json"), `{"state":"queued"}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.running.json"), `{"state":"running"}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.done.json"), `{"state":"done"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func doRequest(s *Server, method, path, body string) (*httptest.ResponseRecorder, map[string]any) {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(method, "http://example.com"+path, strings.NewReader(body))
+	s.handle(rr, req)
+
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	return rr, m
+}
+
+func TestAdmin_SetState_ByPath_ServesThatState(t *testing.T) {
+	s := newScenarioServer(t)
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/scenarios/state", `{"path":"/items/abc","state":"done"}`)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if m["swaggerPath"] != "/items/{id}" || m["key"] != "abc" || m["state"] != "done" {
+		t.Fatalf("unexpected body: %v", m)
+	}
+
+	_, m = doRequest(s, http.MethodGet, "/items/abc", "")
+	if m["state"] != "done" {
+		t.Fatalf("expected jumped state to be served, got %v", m)
+	}
+
+	// other keys are untouched
+	_, m = doRequest(s, http.MethodGet, "/items/other", "")
+	if m["state"] != "queued" {
+		t.Fatalf("expected other key to start at queued, got %v", m)
+	}
+}
+
+func TestAdmin_ListScenarios(t *testing.T) {
+	s := newScenarioServer(t)
+
+	doRequest(s, http.MethodGet, "/items/abc", "")
+	doRequest(s, http.MethodGet, "/items/abc", "")
+
+	rr, m := doRequest(s, http.MethodGet, "/__emulator/scenarios", "")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	list, ok := m["scenarios"].([]any)
+	if !ok || len(list) != 1 {
+		t.Fatalf("expected one active scenario key, got %v", m)
+	}
+	st := list[0].(map[string]any)
+	if st["swaggerPath"] != "/items/{id}" || st["key"] != "abc" || st["state"] != "done" || st["mode"] != "step" {
+		t.Fatalf("unexpected state entry: %v", st)
+	}
+}
+
+func TestAdmin_Reset_OneKeyAndAll(t *testing.T) {
+	s := newScenarioServer(t)
+
+	doRequest(s, http.MethodGet, "/items/a", "")
+	doRequest(s, http.MethodGet, "/items/b", "")
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/scenarios/reset", `{"swaggerPath":"/items/{id}","key":"a"}`)
+	if rr.Code != 200 || m["reset"] != float64(1) {
+		t.Fatalf("expected single reset, got %d %v", rr.Code, m)
+	}
+	_, m = doRequest(s, http.MethodGet, "/items/a", "")
+	if m["state"] != "queued" {
+		t.Fatalf("expected key a to restart, got %v", m)
+	}
+
+	rr, m = doRequest(s, http.MethodPost, "/__emulator/scenarios/reset", "")
+	if rr.Code != 200 || m["reset"] != float64(2) {
+		t.Fatalf("expected reset of both keys, got %d %v", rr.Code, m)
+	}
+	_, m = doRequest(s, http.MethodGet, "/items/b", "")
+	if m["state"] != "queued" {
+		t.Fatalf("expected key b to restart, got %v", m)
+	}
+}
+
+func TestAdmin_Errors(t *testing.T) {
+	s := newScenarioServer(t)
+	// a scenario file without a matching route is never used
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("ghost", "scenario.json"),
+		`{"version":1,"mode":"step","key":{"pathParam":"id"},"sequence":[{"state":"done","file":"GET.json"}]}`)
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/__emulator/scenarios/state", `{"path":"/items/abc","state":"nope"}`, 404},
+		{http.MethodPost, "/__emulator/scenarios/state", `{"path":"/nothing/here","state":"done"}`, 404},
+		{http.MethodPost, "/__emulator/scenarios/state", `{"path":"/items/latest","state":"done"}`, 404},
+		{http.MethodPost, "/__emulator/scenarios/state", `{"swaggerPath":"/ghost","key":"a","state":"done"}`, 404},
+		{http.MethodPost, "/__emulator/scenarios/reset", `{"swaggerPath":"/ghost","key":"a"}`, 404},
+		{http.MethodPost, "/__emulator/scenarios/state", `{"path":"/items/abc"}`, 400},
+		{http.MethodPost, "/__emulator/scenarios/state", `{not json`, 400},
+		{http.MethodGet, "/__emulator/unknown", "", 404},
+	}
+	for _, tc := range cases {
+		rr, _ := doRequest(s, tc.method, tc.path, tc.body)
+		if rr.Code != tc.want {
+			t.Fatalf("%s %s %s: expected %d, got %d: %s", tc.method, tc.path, tc.body, tc.want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestAdmin_ScenariosDisabled_409(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	rr, m := doRequest(s, http.MethodGet, "/__emulator/scenarios", "")
+	if rr.Code != 409 {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if m["error"] != "Scenarios are disabled" {
+		t.Fatalf("unexpected body: %v", m)
+	}
+}
+
+func newTimeScenarioServer(t *testing.T, clock config.ClockMode) *Server {
+	t.Helper()
+
+	prev := config.Envs.Scenario
+	t.Cleanup(func() { config.Envs.Scenario = prev })
+	config.Envs.Scenario = config.ScenarioConfig{Enabled: true, Filename: "scenario.json", Validation: config.ScenarioValidationFail, Clock: clock}
+
+	dir := t.TempDir()
+	spec := strings.Replace(minimalSpec(), `"paths":{`, `"paths":{
+		"/items/latest":{"get":{"responses":{"200":{"description":"ok"}}}},`, 1)
+	specPath := writeFile(t, dir, "spec.json", spec)
+
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "time",
+	  "key": {"pathParam":"id"},
+	  "timeline": [
+		{"afterSec":0,"state":"queued","file":"GET.queued.json"},
+		{"afterSec":60,"state":"running","file":"GET.running.json"},
+		{"afterSec":600,"state":"done","file":"GET.done.json"}
+	  ],
+	  "behavior": {"repeatLast": true}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.queued.json"), `{"state":"queued"}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.running.json"), `{"state":"running"}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.done.json"), `{"state":"done"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestAdmin_Clock_AdvanceGlobalAndPerKey(t *testing.T) {
+	s := newTimeScenarioServer(t, config.ClockVirtual)
+
+	_, m := doRequest(s, http.MethodGet, "/items/a", "")
+	if m["state"] != "queued" {
+		t.Fatalf("expected queued, got %v", m)
+	}
+	doRequest(s, http.MethodGet, "/items/b", "")
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/clock/advance", `{"seconds":61}`)
+	if rr.Code != 200 || m["frozen"] != true {
+		t.Fatalf("expected frozen global clock, got %d %v", rr.Code, m)
+	}
+	_, m = doRequest(s, http.MethodGet, "/items/a", "")
+	if m["state"] != "running" {
+		t.Fatalf("expected running after advance, got %v", m)
+	}
+
+	rr, m = doRequest(s, http.MethodPost, "/__emulator/clock/advance", `{"path":"/items/b","seconds":600}`)
+	if rr.Code != 200 || m["key"] != "b" {
+		t.Fatalf("expected key clock response, got %d %v", rr.Code, m)
+	}
+	_, m = doRequest(s, http.MethodGet, "/items/b", "")
+	if m["state"] != "done" {
+		t.Fatalf("expected done for key b, got %v", m)
+	}
+	_, m = doRequest(s, http.MethodGet, "/items/a", "")
+	if m["state"] != "running" {
+		t.Fatalf("expected key a unaffected, got %v", m)
+	}
+
+	rr, m = doRequest(s, http.MethodGet, "/__emulator/clock", "")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if clocks, ok := m["clocks"].([]any); !ok || len(clocks) != 2 {
+		t.Fatalf("expected global and one key clock, got %v", m)
+	}
+}
+
+func TestAdmin_Clock_SessionsHaveTheirOwnClock(t *testing.T) {
+	s := newTimeScenarioServer(t, config.ClockVirtual)
+
+	state := func(session string) any {
+		_, m := doSessionRequest(s, session, http.MethodGet, "/items/a", "")
+		return m["state"]
+	}
+	for _, session := range []string{"", "x", "y"} {
+		if got := state(session); got != "queued" {
+			t.Fatalf("session %q: expected queued, got %v", session, got)
+		}
+	}
+
+	rr, _ := doSessionRequest(s, "x", http.MethodPost, "/__emulator/clock/advance", `{"seconds":61}`)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := state("x"); got != "running" {
+		t.Fatalf("expected session x to be running, got %v", got)
+	}
+	if got := state("y"); got != "queued" {
+		t.Fatalf("expected session y unaffected, got %v", got)
+	}
+	if got := state(""); got != "queued" {
+		t.Fatalf("expected the default state unaffected, got %v", got)
+	}
+
+	// the global clock still moves every session that did not change its own
+	doRequest(s, http.MethodPost, "/__emulator/clock/advance", `{"seconds":600}`)
+	if got := state("y"); got != "done" {
+		t.Fatalf("expected session y to follow the global clock, got %v", got)
+	}
+}
+
+func TestAdmin_Clock_SetAndErrors(t *testing.T) {
+	s := newTimeScenarioServer(t, config.ClockVirtual)
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/clock/set", `{"time":"2030-01-01T00:00:00Z"}`)
+	if rr.Code != 200 || m["now"] != "2030-01-01T00:00:00Z" {
+		t.Fatalf("unexpected set response: %d %v", rr.Code, m)
+	}
+
+	cases := []struct {
+		path, body string
+		want       int
+	}{
+		{"/__emulator/clock/advance", `{"seconds":-1}`, 400},
+		{"/__emulator/clock/advance", `{}`, 400},
+		{"/__emulator/clock/set", `{"time":"tomorrow"}`, 400},
+		{"/__emulator/clock/rewind", `{}`, 404},
+		{"/__emulator/clock/freeze", `{"path":"/nothing"}`, 404},
+		{"/__emulator/clock/resume", "", 200},
+	}
+	for _, tc := range cases {
+		rr, _ := doRequest(s, http.MethodPost, tc.path, tc.body)
+		if rr.Code != tc.want {
+			t.Fatalf("%s %s: expected %d, got %d: %s", tc.path, tc.body, tc.want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestAdmin_Clock_RealClock_409(t *testing.T) {
+	s := newTimeScenarioServer(t, config.ClockReal)
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/clock/advance", `{"seconds":1}`)
+	if rr.Code != 409 || m["error"] != "Virtual clock is disabled" {
+		t.Fatalf("expected 409, got %d %v", rr.Code, m)
+	}
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/journal"
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// resolution notes how serve answered a request, for the journal.
+type resolution struct {
+	swagger string
+	route   string
+	source  string
+	state   string
+}
+
+// captureWriter passes a response through while keeping a copy of it.
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (cw *captureWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	cw.body.Write(b)
+	return cw.ResponseWriter.Write(b)
+}
+
+// serveJournaled serves a request and adds it to the journal.
+func (s *Server) serveJournaled(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	rc, err := requestContext(r)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+
+	cw := &captureWriter{ResponseWriter: w}
+	res := &resolution{}
+	s.serve(cw, r, res)
+
+	s.journal.Add(journal.Entry{
+		Time:        start.UTC(),
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		Query:       r.URL.RawQuery,
+		SwaggerPath: res.swagger,
+		RoutePath:   routePath(r.URL.Path, res.route),
+		Session:     rc.Session,
+		Source:      res.source,
+		State:       res.state,
+		Request:     journal.NewMessage(0, r.Header, rc.Body),
+		Response:    journal.NewMessage(cw.status, cw.Header(), cw.body.Bytes()),
+		LatencyMs:   float64(time.Since(start).Microseconds()) / 1000,
+	})
+}
+
+// routePath is the path a request was routed by, when the base path was stripped.
+func routePath(path, route string) string {
+	if route == path {
+		return ""
+	}
+	return route
+}
+
+// relativeSource returns a sample path relative to the samples dir when possible.
+func (s *Server) relativeSource(p string) string {
+	if strings.HasPrefix(p, samples.StubSourcePrefix) {
+		return p
+	}
+	if rel, err := filepath.Rel(s.cfg.SamplesDir, p); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return p
+}
+
+func (s *Server) adminListRequests(w http.ResponseWriter, r *http.Request) {
+	if !s.requireJournal(w) {
+		return
+	}
+
+	q := r.URL.Query()
+	f := journal.Filter{
+		Method:      q.Get("method"),
+		Path:        q.Get("path"),
+		SwaggerPath: q.Get("swaggerPath"),
+		Session:     journalSession(r),
+	}
+	for name, dst := range map[string]*int{"status": &f.Status, "limit": &f.Limit} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": name + " must be a non-negative integer"})
+				return
+			}
+			*dst = n
+		}
+	}
+	if v := q.Get("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": "since must be an entry id"})
+			return
+		}
+		f.SinceID = n
+	}
+
+	utils.WriteJSON(w, 200, map[string]any{"requests": s.journal.List(f)})
+}
+
+func (s *Server) adminResetRequests(w http.ResponseWriter) {
+	if !s.requireJournal(w) {
+		return
+	}
+	n := s.journal.Clear()
+	s.log.WithFields(logrus.Fields{"reset": n}).Info("request journal cleared via admin API")
+	utils.WriteJSON(w, 200, map[string]any{"reset": n})
+}
+
+// adminVerifyRequests counts journaled requests matching a pattern and checks the count.
+func (s *Server) adminVerifyRequests(w http.ResponseWriter, r *http.Request) {
+	if !s.requireJournal(w) {
+		return
+	}
+
+	var v journal.Verification
+	if err := decodeAdminBody(r, &v); err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+
+	// a test running in a session only sees its own requests
+	res, err := journal.Verify(s.journal.List(journal.Filter{Session: journalSession(r)}), v)
+	if err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+	utils.WriteJSON(w, 200, res)
+}
+
+// journalSession is the session a journal call is limited to: the session query
+// parameter, or else the session header.
+func journalSession(r *http.Request) string {
+	if id := strings.TrimSpace(r.URL.Query().Get("session")); id != "" {
+		return id
+	}
+	return sessionID(r)
+}
+
+func (s *Server) requireJournal(w http.ResponseWriter) bool {
+	if s.journal != nil {
+		return true
+	}
+	utils.WriteJSON(w, 409, map[string]any{
+		"error": "Request journal is disabled",
+		"hint":  "Set JOURNAL_SIZE to a positive number",
+	})
+	return false
+}
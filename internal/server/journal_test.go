@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
+
+func newJournalServer(t *testing.T, journalFile string) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	writeFileWithDirs(t, dir, filepath.Join("items", "POST.json"), `{"status":201,"body":{"created":true}}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationRequired,
+		Layout:         config.LayoutFolders,
+		Journal:        config.JournalConfig{Size: 10, File: journalFile},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestJournal_RecordsServedRequests(t *testing.T) {
+	journalFile := filepath.Join(t.TempDir(), "journal.jsonl")
+	s := newJournalServer(t, journalFile)
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items?dry=1", strings.NewReader(`{"name":"n1"}`))
+	req.Header.Set("X-Client", "ci")
+	s.handle(httptest.NewRecorder(), req)
+	doRequest(s, http.MethodGet, "/items/7", "")
+	doRequest(s, http.MethodPost, "/items", "")
+	doRequest(s, http.MethodGet, "/health/ready", "")
+
+	_, m := doRequest(s, http.MethodGet, "/__emulator/requests", "")
+	list, _ := m["requests"].([]any)
+	if len(list) != 3 {
+		t.Fatalf("expected 3 journal entries without health/admin calls, got %v", m)
+	}
+
+	first := list[0].(map[string]any)
+	req1 := first["request"].(map[string]any)
+	resp1 := first["response"].(map[string]any)
+	if first["swaggerPath"] != "/items" || first["source"] != "items/POST.json" || first["query"] != "dry=1" {
+		t.Fatalf("unexpected resolution in %v", first)
+	}
+	if req1["body"].(map[string]any)["name"] != "n1" || req1["headers"].(map[string]any)["x-client"] != "ci" {
+		t.Fatalf("unexpected request capture %v", req1)
+	}
+	if resp1["status"] != float64(201) || resp1["body"].(map[string]any)["created"] != true {
+		t.Fatalf("unexpected response capture %v", resp1)
+	}
+
+	second := list[1].(map[string]any)
+	if second["source"] != "openapi_example" || second["swaggerPath"] != "/items/{id}" {
+		t.Fatalf("expected spec example source, got %v", second)
+	}
+
+	_, m = doRequest(s, http.MethodGet, "/__emulator/requests?method=POST&status=400", "")
+	if got, _ := m["requests"].([]any); len(got) != 1 {
+		t.Fatalf("expected the rejected POST only, got %v", m)
+	}
+	_, m = doRequest(s, http.MethodGet, "/__emulator/requests?since=2", "")
+	if got, _ := m["requests"].([]any); len(got) != 1 {
+		t.Fatalf("expected one entry after id 2, got %v", m)
+	}
+	rr, _ := doRequest(s, http.MethodGet, "/__emulator/requests?limit=x", "")
+	if rr.Code != 400 {
+		t.Fatalf("expected 400 for a bad limit, got %d", rr.Code)
+	}
+
+	_, m = doRequest(s, http.MethodPost, "/__emulator/requests/reset", "")
+	if m["reset"] != float64(3) {
+		t.Fatalf("expected 3 entries cleared, got %v", m)
+	}
+
+	b, err := os.ReadFile(journalFile)
+	if err != nil {
+		t.Fatalf("read journal file: %v", err)
+	}
+	if n := strings.Count(string(b), "\n"); n != 3 {
+		t.Fatalf("expected 3 JSONL lines, got %d: %s", n, b)
+	}
+}
+
+func TestJournal_CloseStopsWritingTheFile(t *testing.T) {
+	journalFile := filepath.Join(t.TempDir(), "journal.jsonl")
+	s := newJournalServer(t, journalFile)
+
+	doRequest(s, http.MethodGet, "/items/7", "")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	doRequest(s, http.MethodGet, "/items/8", "")
+	if err := s.Close(); err != nil {
+		t.Fatalf("expected a second Close to be a no-op, got %v", err)
+	}
+
+	b, err := os.ReadFile(journalFile)
+	if err != nil {
+		t.Fatalf("read journal file: %v", err)
+	}
+	if n := strings.Count(string(b), "\n"); n != 1 {
+		t.Fatalf("expected 1 JSONL line written before Close, got %d: %s", n, b)
+	}
+}
+
+func TestJournal_OversizedRequestBody_413(t *testing.T) {
+	s := newJournalServer(t, "")
+
+	rr, _ := doRequest(s, http.MethodPost, "/items", strings.Repeat("x", maxRequestBody+1))
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for an oversized body, got %d", rr.Code)
+	}
+	_, m := doRequest(s, http.MethodGet, "/__emulator/requests", "")
+	if got, _ := m["requests"].([]any); len(got) != 0 {
+		t.Fatalf("expected the oversized request not to be journaled, got %v", m)
+	}
+}
+
+func TestJournal_Disabled_409(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	rr, _ := doRequest(s, http.MethodGet, "/__emulator/requests", "")
+	if rr.Code != 409 {
+		t.Fatalf("expected 409 when the journal is disabled, got %d", rr.Code)
+	}
+}
+
+func TestAdmin_VerifyRequests(t *testing.T) {
+	s := newJournalServer(t, "")
+
+	doRequest(s, http.MethodPost, "/items", `{"name":"first"}`)
+	doRequest(s, http.MethodPost, "/items", `{"name":"second"}`)
+	doRequest(s, http.MethodGet, "/items/7", "")
+
+	_, m := doRequest(s, http.MethodPost, "/__emulator/requests/verify",
+		`{"method":"POST","path":"/items","match":{"body":{"$.name":"second"}},"count":1}`)
+	if m["pass"] != true || m["count"] != float64(1) {
+		t.Fatalf("expected passing verification, got %v", m)
+	}
+
+	_, m = doRequest(s, http.MethodPost, "/__emulator/requests/verify",
+		`{"method":"GET","path":"/items/{id}","match":{"path":{"id":"8"}},"count":1}`)
+	misses, _ := m["nearMisses"].([]any)
+	if m["pass"] != false || len(misses) == 0 {
+		t.Fatalf("expected failure with near misses, got %v", m)
+	}
+	closest := misses[0].(map[string]any)
+	if closest["path"] != "/items/7" {
+		t.Fatalf("expected GET /items/7 as closest miss, got %v", closest)
+	}
+
+	rr, _ := doRequest(s, http.MethodPost, "/__emulator/requests/verify", `{"count":1,"atMost":2}`)
+	if rr.Code != 400 {
+		t.Fatalf("expected 400 for conflicting expectations, got %d", rr.Code)
+	}
+	rr, _ = doRequest(s, http.MethodPost, "/__emulator/requests/verify", `{"match":{"body":{"$.x":{"regex":"("}}}}`)
+	if rr.Code != 400 {
+		t.Fatalf("expected 400 for a broken regex, got %d", rr.Code)
+	}
+}
+
+func TestJournal_SessionFromQueryOrHeader(t *testing.T) {
+	s := newJournalServer(t, "")
+
+	doSessionRequest(s, "x", http.MethodGet, "/items/1", "")
+	doSessionRequest(s, "y", http.MethodGet, "/items/2", "")
+
+	count := func(m map[string]any) int {
+		list, _ := m["requests"].([]any)
+		return len(list)
+	}
+	_, m := doRequest(s, http.MethodGet, "/__emulator/requests?session=x", "")
+	if count(m) != 1 {
+		t.Fatalf("expected one entry for ?session=x, got %v", m)
+	}
+	_, m = doSessionRequest(s, "x", http.MethodGet, "/__emulator/requests", "")
+	if count(m) != 1 {
+		t.Fatalf("expected one entry for the session header, got %v", m)
+	}
+
+	verify := `{"method":"GET","path":"/items/{id}","count":1}`
+	_, m = doRequest(s, http.MethodPost, "/__emulator/requests/verify?session=y", verify)
+	if m["pass"] != true {
+		t.Fatalf("expected ?session=y to count its own request only, got %v", m)
+	}
+	_, m = doSessionRequest(s, "y", http.MethodPost, "/__emulator/requests/verify", verify)
+	if m["pass"] != true {
+		t.Fatalf("expected the session header to count its own request only, got %v", m)
+	}
+}
+
+func TestAdmin_VerifyRequests_BasePathStripped(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+		Journal:        config.JournalConfig{Size: 10},
+		BasePath:       config.BasePathConfig{Paths: []string{"/api/v1"}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	doRequest(s, http.MethodGet, "/api/v1/items/7", "")
+
+	for _, body := range []string{
+		`{"method":"GET","path":"/items/7","count":1}`,
+		`{"method":"GET","path":"/api/v1/items/7","count":1}`,
+		`{"method":"GET","path":"/items/{id}","match":{"path":{"id":"7"}},"count":1}`,
+	} {
+		_, m := doRequest(s, http.MethodPost, "/__emulator/requests/verify", body)
+		if m["pass"] != true {
+			t.Fatalf("%s: expected passing verification, got %v", body, m)
+		}
+	}
+
+	_, m := doRequest(s, http.MethodGet, "/__emulator/requests", "")
+	list, _ := m["requests"].([]any)
+	if len(list) != 1 || list[0].(map[string]any)["routePath"] != "/items/7" {
+		t.Fatalf("expected the route path in the journal, got %v", m)
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+)
+
+// Query parameters that override the Prefer header, e.g. ?__code=404.
+const (
+	preferCodeParam    = "__code"
+	preferExampleParam = "__example"
+	preferDynamicParam = "__dynamic"
+)
+
+// preference reads "Prefer: code=404, example=notFound, dynamic=true" and the
+// equivalent query parameters, which win over the header. Unknown preferences
+// such as return=minimal are ignored.
+func preference(r *http.Request) (openapi.Preference, error) {
+	values := map[string]string{}
+	for _, h := range r.Header.Values("Prefer") {
+		for _, part := range strings.FieldsFunc(h, func(c rune) bool { return c == ',' || c == ';' }) {
+			k, v, _ := strings.Cut(part, "=")
+			values[strings.ToLower(strings.TrimSpace(k))] = strings.Trim(strings.TrimSpace(v), `"`)
+		}
+	}
+	q := r.URL.Query()
+	for key, param := range map[string]string{"code": preferCodeParam, "example": preferExampleParam, "dynamic": preferDynamicParam} {
+		if q.Has(param) {
+			values[key] = strings.TrimSpace(q.Get(param))
+		}
+	}
+
+	var pref openapi.Preference
+	if raw := values["code"]; raw != "" {
+		code, err := strconv.Atoi(raw)
+		if err != nil || code < 100 || code > 599 {
+			return pref, fmt.Errorf("invalid preferred status code %q", raw)
+		}
+		pref.Code = code
+	}
+	pref.Example = values["example"]
+	if raw := values["dynamic"]; raw != "" {
+		dynamic, err := strconv.ParseBool(raw)
+		if err != nil {
+			return pref, fmt.Errorf("invalid dynamic preference %q", raw)
+		}
+		pref.Dynamic = dynamic
+	}
+	return pref, nil
+}
+
+// writePreferenceNotDeclared answers a preference the spec cannot honor.
+func writePreferenceNotDeclared(w http.ResponseWriter, st *specState, rt *openapi.Route, pref openapi.Preference) {
+	var declared []string
+	if op := st.specProvider.FindOperation(rt.Swagger, rt.Method); op != nil && op.Responses != nil {
+		for code := range op.Responses.Map() {
+			declared = append(declared, code)
+		}
+		sort.Strings(declared)
+	}
+
+	utils.WriteJSON(w, 422, map[string]any{
+		"error":       "Preferred response is not declared",
+		"method":      rt.Method,
+		"swaggerPath": rt.Swagger,
+		"prefer":      pref,
+		"declared":    declared,
+		"hint":        "Prefer a status code listed in declared, or an example name of that response",
+	})
+}
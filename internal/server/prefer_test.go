@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+)
+
+func TestPreference_HeaderAndQuery(t *testing.T) {
+	cases := []struct {
+		target string
+		prefer []string
+		want   openapi.Preference
+		err    bool
+	}{
+		{"/x", nil, openapi.Preference{}, false},
+		{"/x", []string{"code=404, example=notFound"}, openapi.Preference{Code: 404, Example: "notFound"}, false},
+		{"/x", []string{`return=minimal; example="a b"`, "dynamic=true"}, openapi.Preference{Example: "a b", Dynamic: true}, false},
+		{"/x?__code=409&__dynamic=1", []string{"code=404"}, openapi.Preference{Code: 409, Dynamic: true}, false},
+		{"/x?__example=done", nil, openapi.Preference{Example: "done"}, false},
+		{"/x", []string{"code=abc"}, openapi.Preference{}, true},
+		{"/x?__code=700", nil, openapi.Preference{}, true},
+		{"/x", []string{"dynamic=maybe"}, openapi.Preference{}, true},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		for _, h := range tc.prefer {
+			r.Header.Add("Prefer", h)
+		}
+		got, err := preference(r)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("%s %v: expected error", tc.target, tc.prefer)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("%s %v: expected %+v, got %+v (%v)", tc.target, tc.prefer, tc.want, got, err)
+		}
+	}
+}
+
+func newPreferServer(t *testing.T) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans/{id}":{
+		  "get":{
+			"responses":{
+			  "200":{"description":"ok","content":{"application/json":{
+				"schema":{"type":"object","properties":{"id":{"type":"string","format":"uuid"}}},
+				"examples":{"running":{"value":{"status":"running"}}}
+			  }}},
+			  "404":{"description":"missing","content":{"application/json":{"example":{"error":"not found"}}}},
+			  "409":{"description":"busy","content":{"application/json":{"example":{"error":"busy"}}}}
+			}
+		  }
+		}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("scans", "{id}", "GET.json"), `{"id":"sample"}`)
+	writeFileWithDirs(t, dir, filepath.Join("scans", "{id}", "GET.status-409.json"), `{"status":409,"body":{"error":"busy sample"}}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func doPrefer(s *Server, target, prefer string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com"+target, nil)
+	if prefer != "" {
+		req.Header.Set("Prefer", prefer)
+	}
+	s.handle(rr, req)
+	return rr
+}
+
+func TestHandle_Prefer_SelectsResponse(t *testing.T) {
+	s := newPreferServer(t)
+
+	cases := []struct {
+		name, target, prefer string
+		code                 int
+		body                 string
+	}{
+		{"sample without preference", "/scans/a", "", 200, `{"id":"sample"}`},
+		{"spec status", "/scans/a", "code=404", 404, `{"error":"not found"}`},
+		{"status query", "/scans/a?__code=404", "", 404, `{"error":"not found"}`},
+		{"per-status sample", "/scans/a", "code=409", 409, `{"error":"busy sample"}`},
+		{"named example", "/scans/a", "example=running", 200, `{"status":"running"}`},
+		{"dynamic", "/scans/a?__dynamic=true", "", 200, `{"id":"3fa85f64-5717-4562-b3fc-2c963f66afa6"}`},
+	}
+	for _, tc := range cases {
+		rr := doPrefer(s, tc.target, tc.prefer)
+		if rr.Code != tc.code || rr.Body.String() != tc.body {
+			t.Fatalf("%s: expected %d %s, got %d %s", tc.name, tc.code, tc.body, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestHandle_Prefer_StubsStillMatchFirst(t *testing.T) {
+	s := newPreferServer(t)
+
+	rr, _ := doRequest(s, http.MethodPost, "/__emulator/stubs", `{
+	  "path": "/scans/{id}",
+	  "match": {"path": {"id": "stubbed"}},
+	  "response": {"status": 503, "body": {"from": "stub"}}
+	}`)
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	for _, prefer := range []string{"example=running", "dynamic=true", "code=404"} {
+		rr := doPrefer(s, "/scans/stubbed", prefer)
+		if rr.Code != 503 || rr.Body.String() != `{"from":"stub"}` {
+			t.Fatalf("%s: expected the stub, got %d %s", prefer, rr.Code, rr.Body.String())
+		}
+	}
+	if rr := doPrefer(s, "/scans/other", "example=running"); rr.Body.String() != `{"status":"running"}` {
+		t.Fatalf("expected the spec example without a matching stub, got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_Prefer_Errors(t *testing.T) {
+	s := newPreferServer(t)
+
+	rr, m := doRequest(s, http.MethodGet, "/scans/a?__code=418", "")
+	if rr.Code != 422 {
+		t.Fatalf("expected 422 for an undeclared status, got %d %s", rr.Code, rr.Body.String())
+	}
+	declared, _ := m["declared"].([]any)
+	if len(declared) != 3 || declared[0] != "200" || declared[2] != "409" {
+		t.Fatalf("expected declared codes, got %v", m)
+	}
+
+	if rr := doPrefer(s, "/scans/a", "example=paused"); rr.Code != 422 {
+		t.Fatalf("expected 422 for an unknown example, got %d", rr.Code)
+	}
+	if rr := doPrefer(s, "/scans/a", "code=four"); rr.Code != 400 {
+		t.Fatalf("expected 400 for a malformed preference, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+	"github.com/greenbone/gvm-openapi-emulator/internal/watch"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// specState is one loaded version of the spec, its routes and the preloaded scenarios.
+type specState struct {
+	specProvider   openapi.ISpecProvider
+	routerProvider openapi.IRouterProvider
+	validator      openapi.IValidator
+	sampleProvider samples.ISampleProvider
+	scenarios      map[string]*samples.Scenario
+}
+
+func (s *Server) current() *specState {
+	return s.spec.Load()
+}
+
+// loadSpecState reads the spec and the scenarios from disk.
+func (s *Server) loadSpecState() (*specState, error) {
+	gen := openapi.GeneratorConfig{
+		Seeded:   s.cfg.Generator.Mode == config.GeneratorSeeded,
+		Seed:     int64(s.cfg.Generator.Seed),
+		ArrayMin: s.cfg.Generator.ArrayMin,
+		ArrayMax: s.cfg.Generator.ArrayMax,
+	}
+	specProvider, err := openapi.NewSpecProviderWithGenerator(s.cfg.SpecPath, gen, s.log)
+	if err != nil {
+		return nil, err
+	}
+
+	sp, ok := specProvider.(*openapi.SpecProvider)
+	if !ok {
+		return nil, fmt.Errorf("unexpected spec provider type: %T", specProvider)
+	}
+
+	base := openapi.BaseConfig{
+		Paths:   s.cfg.BasePath.Paths,
+		Require: s.cfg.BasePath.Mode == config.BasePathRequire,
+	}
+	st := &specState{
+		specProvider:   specProvider,
+		routerProvider: openapi.NewRouterProviderWithBase(sp.GetSpec(), base),
+		validator:      openapi.NewValidator(specProvider),
+	}
+
+	providerCfg := s.providerCfg
+	if config.Envs.Scenario.Enabled {
+		scenarios, err := samples.LoadScenarios(s.cfg.SamplesDir, config.Envs.Scenario.Filename, routeOperations(st.routerProvider))
+		if err != nil {
+			if config.Envs.Scenario.Validation != config.ScenarioValidationWarn {
+				return nil, err
+			}
+			s.log.Warn(err.Error())
+		}
+		s.log.Infof("preloaded %d scenario(s)", len(scenarios))
+		providerCfg.Scenarios = scenarios
+		st.scenarios = scenarios
+	}
+
+	st.sampleProvider = samples.NewSampleProvider(providerCfg, s.log)
+	return st, nil
+}
+
+// Reload loads the spec and scenarios again and swaps them in. When loading
+// fails the running version keeps serving. Sample files are read per request,
+// so their edits need no reload. Scenario state is reset when a scenario changed.
+func (s *Server) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	st, err := s.loadSpecState()
+	if err != nil {
+		s.log.WithError(err).Error("reload failed, keeping the previous version")
+		return err
+	}
+
+	prev := s.spec.Swap(st)
+	if s.scenario != nil && !reflect.DeepEqual(prev.scenarios, st.scenarios) {
+		n := s.scenario.ResetAll()
+		for _, info := range s.sessions.List() {
+			n += s.sessions.Resolver(info.ID).ResetAll()
+		}
+		s.log.WithFields(logrus.Fields{"reset": n}).Info("scenarios changed, scenario state reset")
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"spec":   s.cfg.SpecPath,
+		"routes": len(st.routerProvider.GetRoutes()),
+	}).Info("spec and scenarios reloaded")
+	return nil
+}
+
+// watch reloads whenever the spec or the samples dir change, until stop is closed.
+func (s *Server) watch(stop <-chan struct{}) {
+	w := watch.NewWatcher(watch.Config{
+		Paths:    []string{s.cfg.SpecPath, s.cfg.SamplesDir},
+		Interval: time.Duration(s.cfg.Watch.IntervalMs) * time.Millisecond,
+	}, s.log)
+	w.Run(stop, func() { _ = s.Reload() })
+}
+
+func (s *Server) adminReload(w http.ResponseWriter) {
+	if err := s.Reload(); err != nil {
+		utils.WriteJSON(w, 500, map[string]any{
+			"error":   "Reload failed, previous version still served",
+			"details": err.Error(),
+		})
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"reloaded": true, "routes": len(s.current().routerProvider.GetRoutes())})
+}
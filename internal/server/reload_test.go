@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
+
+func TestReload_SwapsSpecAndKeepsOldOnFailure(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr, _ := doRequest(s, http.MethodGet, "/scans", "")
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 before the route exists, got %d", rr.Code)
+	}
+
+	withScans := strings.Replace(minimalSpec(), `"paths":{`, `"paths":{
+		"/scans":{"get":{"responses":{"200":{"description":"ok",
+		  "content":{"application/json":{"example":{"scans":[]}}}}}}},`, 1)
+	if withScans == minimalSpec() {
+		t.Fatalf("test spec did not change")
+	}
+	writeFile(t, dir, "spec.json", withScans)
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/reload", "")
+	if rr.Code != 200 || m["reloaded"] != true {
+		t.Fatalf("expected reload, got %d %v", rr.Code, m)
+	}
+	rr, m = doRequest(s, http.MethodGet, "/scans", "")
+	if rr.Code != 200 || m["scans"] == nil {
+		t.Fatalf("expected new route to be served, got %d %v", rr.Code, m)
+	}
+
+	writeFile(t, dir, "spec.json", `{"openapi":`)
+	rr, m = doRequest(s, http.MethodPost, "/__emulator/reload", "")
+	if rr.Code != 500 || m["details"] == nil {
+		t.Fatalf("expected failed reload, got %d %v", rr.Code, m)
+	}
+	rr, _ = doRequest(s, http.MethodGet, "/scans", "")
+	if rr.Code != 200 {
+		t.Fatalf("expected previous version to keep serving, got %d", rr.Code)
+	}
+}
+
+func TestReload_ResetsStateOnlyWhenScenariosChange(t *testing.T) {
+	s := newScenarioServer(t)
+	dir := s.cfg.SamplesDir
+
+	doRequest(s, http.MethodGet, "/items/abc", "")
+	doRequest(s, http.MethodGet, "/items/abc", "")
+
+	// a sample edit keeps the scenario where it is
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.done.json"), `{"state":"finished"}`)
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	_, m := doRequest(s, http.MethodGet, "/items/abc", "")
+	if m["state"] != "finished" {
+		t.Fatalf("expected state to survive and the edited sample to be served, got %v", m)
+	}
+
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam":"id"},
+	  "sequence": [
+		{"state":"running","file":"GET.running.json"},
+		{"state":"done","file":"GET.done.json"}
+	  ],
+	  "behavior": {"advanceOn":[{"method":"GET"}], "repeatLast": true}
+	}`)
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	_, m = doRequest(s, http.MethodGet, "/items/abc", "")
+	if m["state"] != "running" {
+		t.Fatalf("expected the changed scenario to start over, got %v", m)
+	}
+
+	// an invalid scenario fails the reload and keeps the previous one
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "scenario.json"), `{"version": 1, "mode": "step"}`)
+	if err := s.Reload(); err == nil {
+		t.Fatalf("expected reload to fail on an invalid scenario")
+	}
+	_, m = doRequest(s, http.MethodGet, "/items/abc", "")
+	if m["state"] != "finished" {
+		t.Fatalf("expected previous scenario to keep serving, got %v", m)
+	}
+}
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+)
+
+// serveResource answers CRUD-shaped routes from the in-memory resource store.
+// A collection route (e.g. /scans) has an item route (/scans/{id}) in the spec and the
+// other way round, and only collections that accept POST are resources; read-only
+// ones such as /vts/{oid} keep their samples. It returns false when the route is not
+// handled in resource mode, so the request falls through to sample resolution.
+func (s *Server) serveResource(w http.ResponseWriter, r *http.Request, st *specState, rt *openapi.Route, base string) bool {
+	// scenarios own their paths
+	if _, ok := st.scenarios[rt.Swagger]; ok {
+		return false
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+
+	if coll, _, ok := st.routerProvider.CollectionOf(rt.Swagger); ok && st.creatable(coll) {
+		i := strings.LastIndex(path, "/")
+		coll, id := path[:i], path[i+1:]
+		if unescaped, err := url.PathUnescape(id); err == nil {
+			id = unescaped
+		}
+
+		switch rt.Method {
+		case http.MethodGet:
+			res, ok := s.resources.Get(coll, id)
+			if !ok {
+				st.writeResourceNotFound(w, rt, path)
+				return true
+			}
+			s.writeResource(w, st.successStatus(rt, 200), res)
+		case http.MethodPut:
+			body, ok := readResourceBody(w, r)
+			if !ok {
+				return true
+			}
+			res, created := s.resources.Put(coll, id, body)
+			status := 200
+			if created {
+				status = 201
+			}
+			s.writeResource(w, st.successStatus(rt, status), res)
+		case http.MethodPatch:
+			existing, ok := s.resources.Get(coll, id)
+			if !ok {
+				st.writeResourceNotFound(w, rt, path)
+				return true
+			}
+			body, ok := readResourceBody(w, r)
+			if !ok {
+				return true
+			}
+			for k, v := range body {
+				existing[k] = v
+			}
+			res, _ := s.resources.Put(coll, id, existing)
+			s.writeResource(w, st.successStatus(rt, 200), res)
+		case http.MethodDelete:
+			if !s.resources.Delete(coll, id) {
+				st.writeResourceNotFound(w, rt, path)
+				return true
+			}
+			s.writeResource(w, st.successStatus(rt, 204), nil)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if _, ok := st.routerProvider.ItemOf(rt.Swagger); ok && st.creatable(rt.Swagger) {
+		switch rt.Method {
+		case http.MethodGet:
+			s.writeResource(w, st.successStatus(rt, 200), s.resources.List(path))
+		case http.MethodPost:
+			body, ok := readResourceBody(w, r)
+			if !ok {
+				return true
+			}
+			id, res, err := s.resources.Create(path, body)
+			if err != nil {
+				utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+				return true
+			}
+			w.Header().Set("location", base+path+"/"+url.PathEscape(id))
+			s.writeResource(w, st.successStatus(rt, 201), res)
+		default:
+			return false
+		}
+		return true
+	}
+
+	return false
+}
+
+// creatable reports whether the spec lets clients create items in a collection.
+func (st *specState) creatable(collection string) bool {
+	return st.specProvider.FindOperation(collection, http.MethodPost) != nil
+}
+
+// successStatus returns preferred when the operation declares it (or declares no
+// success status at all), otherwise the lowest declared 2xx status.
+func (st *specState) successStatus(rt *openapi.Route, preferred int) int {
+	op := st.specProvider.FindOperation(rt.Swagger, rt.Method)
+	if op == nil || op.Responses == nil {
+		return preferred
+	}
+	if op.Responses.Value(strconv.Itoa(preferred)) != nil {
+		return preferred
+	}
+
+	var codes []int
+	for k := range op.Responses.Map() {
+		if n, err := strconv.Atoi(k); err == nil && n >= 200 && n < 300 {
+			codes = append(codes, n)
+		}
+	}
+	if len(codes) == 0 {
+		return preferred
+	}
+	sort.Ints(codes)
+	return codes[0]
+}
+
+func (s *Server) writeResource(w http.ResponseWriter, status int, v any) {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	utils.WriteJSON(w, status, v)
+}
+
+// writeResourceNotFound answers with the spec's 404 response when it declares one.
+func (st *specState) writeResourceNotFound(w http.ResponseWriter, rt *openapi.Route, path string) {
+	if body, ok := st.specProvider.TryGetStatusExampleBody(rt.Swagger, rt.Method, 404, rt.Params); ok {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(404)
+		_, _ = w.Write(body) // #nosec G705: XSS via taint analysis
+		return
+	}
+
+	utils.WriteJSON(w, 404, map[string]any{
+		"error":       "Resource not found",
+		"method":      rt.Method,
+		"path":        path,
+		"swaggerPath": rt.Swagger,
+	})
+}
+
+// readResourceBody decodes a JSON object request body, answering 400 otherwise.
+func readResourceBody(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	var body map[string]any
+
+	b, err := io.ReadAll(r.Body)
+	if err == nil {
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		err = d.Decode(&body)
+	}
+	if err == nil && body == nil {
+		err = fmt.Errorf("body must be a JSON object")
+	}
+	if err != nil {
+		utils.WriteJSON(w, 400, map[string]any{
+			"error":   "Bad Request",
+			"details": fmt.Sprintf("resource body must be a JSON object: %v", err),
+		})
+		return nil, false
+	}
+	return body, true
+}
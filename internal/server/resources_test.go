@@ -0,0 +1,323 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
+
+func newResourceServer(t *testing.T) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans":{
+		  "get":{"responses":{"200":{"description":"ok"}}},
+		  "post":{"responses":{"201":{"description":"created"}}}
+		},
+		"/scans/{id}":{
+		  "get":{
+			"responses":{
+			  "200":{"description":"ok"},
+			  "404":{
+				"description":"missing",
+				"content":{"application/json":{"example":{"code":404,"message":"scan not found"}}}
+			  }
+			}
+		  },
+		  "put":{"responses":{"200":{"description":"ok"}}},
+		  "patch":{"responses":{"200":{"description":"ok"}}},
+		  "delete":{"responses":{"204":{"description":"gone"}}}
+		},
+		"/scans/{id}/results":{
+		  "get":{"responses":{"200":{"description":"ok"}}},
+		  "post":{"responses":{"200":{"description":"ok"}}}
+		},
+		"/scans/{id}/results/{rid}":{
+		  "get":{"responses":{"200":{"description":"ok"}}}
+		},
+		"/health/version":{
+		  "get":{"responses":{"200":{"description":"ok"}}}
+		}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("health", "version", "GET.json"), `{"version":"1.0"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+		Resources:      config.ResourceConfig{Enabled: true, IDField: "id"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestResources_CreateThenRead(t *testing.T) {
+	s := newResourceServer(t)
+
+	rr, m := doRequest(s, http.MethodPost, "/scans", `{"target":"10.0.0.1"}`)
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	id, _ := m["id"].(string)
+	if id == "" || m["target"] != "10.0.0.1" {
+		t.Fatalf("expected generated id and stored body, got %v", m)
+	}
+	if loc := rr.Header().Get("location"); loc != "/scans/"+id {
+		t.Fatalf("unexpected location %q", loc)
+	}
+
+	rr, m = doRequest(s, http.MethodGet, "/scans/"+id, "")
+	if rr.Code != 200 || m["target"] != "10.0.0.1" {
+		t.Fatalf("expected stored resource, got %d %v", rr.Code, m)
+	}
+
+	doRequest(s, http.MethodPost, "/scans", `{"id":"fixed","target":"10.0.0.2"}`)
+
+	rr, _ = doRequest(s, http.MethodGet, "/scans", "")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, `"id":"`+id+`"`) || !strings.Contains(body, `"id":"fixed"`) {
+		t.Fatalf("expected both resources in list, got %s", body)
+	}
+}
+
+func TestResources_MissingItem_UsesSpec404(t *testing.T) {
+	s := newResourceServer(t)
+
+	rr, m := doRequest(s, http.MethodGet, "/scans/nope", "")
+	if rr.Code != 404 || m["message"] != "scan not found" {
+		t.Fatalf("expected spec-shaped 404, got %d %v", rr.Code, m)
+	}
+
+	// DELETE declares no 404, so the emulator's own error is used
+	rr, m = doRequest(s, http.MethodDelete, "/scans/nope", "")
+	if rr.Code != 404 || m["error"] != "Resource not found" {
+		t.Fatalf("expected generic 404, got %d %v", rr.Code, m)
+	}
+}
+
+func TestResources_UpdateAndDelete(t *testing.T) {
+	s := newResourceServer(t)
+
+	doRequest(s, http.MethodPut, "/scans/abc", `{"target":"a","state":"new"}`)
+
+	rr, m := doRequest(s, http.MethodPatch, "/scans/abc", `{"state":"running"}`)
+	if rr.Code != 200 || m["target"] != "a" || m["state"] != "running" || m["id"] != "abc" {
+		t.Fatalf("expected merged resource, got %d %v", rr.Code, m)
+	}
+
+	rr, _ = doRequest(s, http.MethodDelete, "/scans/abc", "")
+	if rr.Code != 204 || rr.Body.Len() != 0 {
+		t.Fatalf("expected empty 204, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	rr, _ = doRequest(s, http.MethodGet, "/scans/abc", "")
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 after delete, got %d", rr.Code)
+	}
+}
+
+func TestResources_NestedCollections(t *testing.T) {
+	s := newResourceServer(t)
+
+	// POST /scans/{id}/results only declares 200
+	rr, m := doRequest(s, http.MethodPost, "/scans/abc/results", `{"id":"r1","severity":5}`)
+	if rr.Code != 200 || m["id"] != "r1" {
+		t.Fatalf("expected declared 200, got %d %v", rr.Code, m)
+	}
+
+	rr, _ = doRequest(s, http.MethodGet, "/scans/abc/results/r1", "")
+	if rr.Code != 200 || strings.TrimSpace(rr.Body.String()) != `{"id":"r1","severity":5}` {
+		t.Fatalf("unexpected nested item: %d %s", rr.Code, rr.Body.String())
+	}
+
+	rr, _ = doRequest(s, http.MethodGet, "/scans/other/results", "")
+	if strings.TrimSpace(rr.Body.String()) != `[]` {
+		t.Fatalf("expected empty list for another scan, got %s", rr.Body.String())
+	}
+}
+
+func TestResources_BadBodyAndNonResourceRoutes(t *testing.T) {
+	s := newResourceServer(t)
+
+	rr, _ := doRequest(s, http.MethodPost, "/scans", `[1,2]`)
+	if rr.Code != 400 {
+		t.Fatalf("expected 400 for non-object body, got %d", rr.Code)
+	}
+	rr, _ = doRequest(s, http.MethodPost, "/scans", `{"id":"a/b"}`)
+	if rr.Code != 400 {
+		t.Fatalf("expected 400 for an id with a slash, got %d", rr.Code)
+	}
+
+	// routes without a collection/item pair keep using samples
+	_, m := doRequest(s, http.MethodGet, "/health/version", "")
+	if m["version"] != "1.0" {
+		t.Fatalf("expected sample for non-resource route, got %v", m)
+	}
+}
+
+func TestAdmin_Resources_ListAndReset(t *testing.T) {
+	s := newResourceServer(t)
+
+	doRequest(s, http.MethodPost, "/scans", `{}`)
+	doRequest(s, http.MethodPost, "/scans/abc/results", `{}`)
+
+	_, m := doRequest(s, http.MethodGet, "/__emulator/resources", "")
+	cols, _ := m["collections"].(map[string]any)
+	if cols["/scans"] != float64(1) || cols["/scans/abc/results"] != float64(1) {
+		t.Fatalf("unexpected collections: %v", m)
+	}
+
+	_, m = doRequest(s, http.MethodPost, "/__emulator/resources/reset", "")
+	if m["reset"] != float64(2) {
+		t.Fatalf("expected 2 resources reset, got %v", m)
+	}
+
+	off := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	rr, _ := doRequest(off, http.MethodGet, "/__emulator/resources", "")
+	if rr.Code != 409 {
+		t.Fatalf("expected 409 when resources are disabled, got %d", rr.Code)
+	}
+}
+
+func TestResources_LocationKeepsBasePath(t *testing.T) {
+	s := newResourceServer(t)
+	s.cfg.BasePath = config.BasePathConfig{Paths: []string{"/api/v1"}}
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	rr, _ := doRequest(s, http.MethodPost, "/api/v1/scans", `{"id":"abc"}`)
+	if loc := rr.Header().Get("location"); rr.Code != 201 || loc != "/api/v1/scans/abc" {
+		t.Fatalf("expected location below the base path, got %d %q", rr.Code, loc)
+	}
+	rr, _ = doRequest(s, http.MethodGet, "/api/v1/scans/abc", "")
+	if rr.Code != 200 {
+		t.Fatalf("expected stored resource, got %d", rr.Code)
+	}
+}
+
+func TestResources_DeleteStillResetsScenario(t *testing.T) {
+	prev := config.Envs.Scenario
+	defer func() { config.Envs.Scenario = prev }()
+	config.Envs.Scenario.Enabled = true
+	config.Envs.Scenario.Filename = "scenario.json"
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans":{"post":{"responses":{"201":{"description":"created"}}}},
+		"/scans/{id}":{
+		  "get":{"responses":{"200":{"description":"ok"}}},
+		  "delete":{"responses":{"204":{"description":"gone"}}}
+		},
+		"/scans/{id}/status":{"get":{"responses":{"200":{"description":"ok"}}}}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("scans", "{id}", "status", "scenario.json"), `{
+	  "version":1,"mode":"step","key":{"pathParam":"id"},
+	  "sequence":[{"state":"requested","file":"GET.requested.json"},{"state":"done","file":"GET.done.json"}],
+	  "behavior":{"advanceOn":[{"method":"GET"}],"resetOn":[{"method":"DELETE","path":"/scans/{id}"}],"repeatLast":true}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("scans", "{id}", "status", "GET.requested.json"), `{"status":"requested"}`)
+	writeFileWithDirs(t, dir, filepath.Join("scans", "{id}", "status", "GET.done.json"), `{"status":"done"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+		Resources:      config.ResourceConfig{Enabled: true, IDField: "id"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	doRequest(s, http.MethodPost, "/scans", `{"id":"a"}`)
+	doRequest(s, http.MethodGet, "/scans/a/status", "")
+	if _, m := doRequest(s, http.MethodGet, "/scans/a/status", ""); m["status"] != "done" {
+		t.Fatalf("expected the scenario to advance, got %v", m)
+	}
+
+	if rr, _ := doRequest(s, http.MethodDelete, "/scans/a", ""); rr.Code != 204 {
+		t.Fatalf("expected the resource to be deleted, got %d", rr.Code)
+	}
+	if _, m := doRequest(s, http.MethodGet, "/scans/a/status", ""); m["status"] != "requested" {
+		t.Fatalf("expected DELETE to reset the scenario, got %v", m)
+	}
+}
+
+func TestResources_ReadOnlyCollectionsKeepSamples(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/vts":{"get":{"responses":{"200":{"description":"ok"}}}},
+		"/vts/{oid}":{"get":{"responses":{"200":{"description":"ok"}}}}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("vts", "GET.json"), `["1.2.3"]`)
+	writeFileWithDirs(t, dir, filepath.Join("vts", "{oid}", "GET.json"), `{"oid":"1.2.3"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+		Resources:      config.ResourceConfig{Enabled: true, IDField: "id"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if rr, m := doRequest(s, http.MethodGet, "/vts/1.2.3", ""); rr.Code != 200 || m["oid"] != "1.2.3" {
+		t.Fatalf("expected the item sample, got %d %v", rr.Code, m)
+	}
+	if rr, _ := doRequest(s, http.MethodGet, "/vts", ""); strings.TrimSpace(rr.Body.String()) != `["1.2.3"]` {
+		t.Fatalf("expected the collection sample, got %s", rr.Body.String())
+	}
+}
+
+func TestResources_StubsAnswerFirst(t *testing.T) {
+	s := newResourceServer(t)
+
+	doRequest(s, http.MethodPost, "/scans", `{"id":"a","name":"first"}`)
+	doRequest(s, http.MethodPost, "/__emulator/stubs", `{"method":"GET","path":"/scans/a","times":1,"response":{"status":503}}`)
+
+	if rr, _ := doRequest(s, http.MethodGet, "/scans/a", ""); rr.Code != 503 {
+		t.Fatalf("expected the stub before the stored resource, got %d", rr.Code)
+	}
+	rr, m := doRequest(s, http.MethodGet, "/scans/a", "")
+	if rr.Code != 200 || m["name"] != "first" {
+		t.Fatalf("expected the stored resource once the stub is used up, got %d %v", rr.Code, m)
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// ResponseViolationsHeader carries a summary of sample drift in RESPONSE_VALIDATION_MODE=header.
+const ResponseViolationsHeader = "X-Emulator-Response-Violations"
+
+// checkSampleResponse validates a loaded sample against the spec's response
+// schemas according to the configured response validation mode.
+// It returns false when the response has already been written (fail mode).
+func (s *Server) checkSampleResponse(w http.ResponseWriter, st *specState, rt *openapi.Route, resp *samples.Response) bool {
+	mode := s.cfg.ResponseValidationMode
+	if mode == "" || mode == config.ResponseValidationNone {
+		return true
+	}
+
+	issues := st.validator.ValidateResponse(rt, resp.Status, resp.Headers, resp.Body)
+	if len(issues) == 0 {
+		return true
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"method":      rt.Method,
+		"swaggerPath": rt.Swagger,
+		"status":      resp.Status,
+		"file":        resp.Source,
+		"violations":  formatIssues(issues),
+	}).Warn("sample does not conform to the API spec")
+
+	switch mode {
+	case config.ResponseValidationHeader:
+		w.Header().Set(ResponseViolationsHeader, formatIssues(issues))
+	case config.ResponseValidationFail:
+		utils.WriteJSON(w, 500, map[string]any{
+			"error":       "Sample does not conform to the API spec",
+			"method":      rt.Method,
+			"swaggerPath": rt.Swagger,
+			"status":      resp.Status,
+			"file":        resp.Source,
+			"violations":  issues,
+		})
+		return false
+	}
+
+	return true
+}
+
+func formatIssues(issues []openapi.ValidationIssue) string {
+	parts := make([]string, 0, len(issues))
+	for _, is := range issues {
+		msg := strings.Join(strings.Fields(is.Message), " ")
+		parts = append(parts, fmt.Sprintf("%s: %s", is.Pointer, msg))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
+
+func newResponseValidationServer(t *testing.T, mode config.ResponseValidationMode) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/items/{id}":{
+		  "get":{
+			"responses":{
+			  "200":{
+				"description":"ok",
+				"content":{
+				  "application/json":{
+					"schema":{
+					  "type":"object",
+					  "required":["id"],
+					  "properties":{"id":{"type":"string"}}
+					}
+				  }
+				}
+			  }
+			}
+		  }
+		},
+		"/reports/{id}":{
+		  "get":{"responses":{"200":{"description":"ok","content":{"application/xml":{"schema":{"type":"object"}}}}}}
+		},
+		"/items":{
+		  "get":{
+			"responses":{
+			  "200":{
+				"description":"ok",
+				"content":{
+				  "application/json":{
+					"schema":{"type":"array","items":{"type":"string"}}
+				  }
+				}
+			  }
+			}
+		  }
+		}
+	  }
+	}`)
+
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"), `{"body":{"id":123}}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "GET.json"), `["a","b"]`)
+	writeFileWithDirs(t, dir, filepath.Join("reports", "{id}", "GET.xml"), `<report id="1"/>`)
+
+	s, err := New(Config{
+		Port:                   "0",
+		SpecPath:               specPath,
+		SamplesDir:             dir,
+		FallbackMode:           config.FallbackNone,
+		ValidationMode:         config.ValidationNone,
+		Layout:                 config.LayoutFolders,
+		ResponseValidationMode: mode,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestHandle_ResponseValidationWarn_ServesSample(t *testing.T) {
+	s := newResponseValidationServer(t, config.ResponseValidationWarn)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if h := rr.Header().Get(ResponseViolationsHeader); h != "" {
+		t.Fatalf("expected no violations header in warn mode, got %q", h)
+	}
+}
+
+func TestHandle_ResponseValidationHeader_ReportsDrift(t *testing.T) {
+	s := newResponseValidationServer(t, config.ResponseValidationHeader)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	h := rr.Header().Get(ResponseViolationsHeader)
+	if !strings.HasPrefix(h, "/body/id: ") {
+		t.Fatalf("unexpected violations header: %q", h)
+	}
+	if strings.TrimSpace(rr.Body.String()) != `{"id":123}` {
+		t.Fatalf("unexpected body: %q", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items", nil))
+	if h := rr.Header().Get(ResponseViolationsHeader); h != "" {
+		t.Fatalf("expected no violations header for valid sample, got %q", h)
+	}
+}
+
+func TestHandle_ResponseValidationFail_500(t *testing.T) {
+	s := newResponseValidationServer(t, config.ResponseValidationFail)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+
+	if rr.Code != 500 {
+		t.Fatalf("expected 500, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != "Sample does not conform to the API spec" {
+		t.Fatalf("unexpected: %v", m)
+	}
+	if !strings.HasSuffix(m["file"].(string), filepath.Join("items", "{id}", "GET.json")) {
+		t.Fatalf("unexpected file: %v", m["file"])
+	}
+	if v, ok := m["violations"].([]any); !ok || len(v) != 1 {
+		t.Fatalf("unexpected violations: %v", m["violations"])
+	}
+}
+
+func TestHandle_ResponseValidationFail_UndecodableBodyChecksStatusAndHeadersOnly(t *testing.T) {
+	s := newResponseValidationServer(t, config.ResponseValidationFail)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/reports/1", nil))
+
+	if rr.Code != 200 || rr.Header().Get("content-type") != "application/xml" {
+		t.Fatalf("expected XML sample, got %d %v: %s", rr.Code, rr.Header(), rr.Body.String())
+	}
+	if rr.Body.String() != `<report id="1"/>` {
+		t.Fatalf("unexpected body: %q", rr.Body.String())
+	}
+}
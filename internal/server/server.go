@@ -5,13 +5,29 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/journal"
 	"github.com/greenbone/gvm-openapi-emulator/internal/openapi"
+	"github.com/greenbone/gvm-openapi-emulator/internal/proxy"
+	"github.com/greenbone/gvm-openapi-emulator/internal/recorder"
+	"github.com/greenbone/gvm-openapi-emulator/internal/resources"
 	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
 	"github.com/greenbone/gvm-openapi-emulator/logger"
 	"github.com/greenbone/gvm-openapi-emulator/utils"
@@ -25,64 +41,144 @@ type Config struct {
 	FallbackMode   config.FallbackMode
 	ValidationMode config.ValidationMode
 	Layout         config.LayoutMode
+
+	ResponseValidationMode config.ResponseValidationMode
+	Resources              config.ResourceConfig
+	Templates              bool
+
+	Journal  config.JournalConfig
+	Upstream config.UpstreamConfig
+	// Record forwards every request to Upstream and writes the responses as samples.
+	Record bool
+
+	Watch config.WatchConfig
+	// BasePath overrides or enforces the base paths declared in the spec.
+	BasePath config.BasePathConfig
+	// Generator sets up bodies generated from response schemas.
+	Generator config.GeneratorConfig
 }
 
 type Server struct {
-	cfg            Config
-	specProvider   openapi.ISpecProvider
-	routerProvider openapi.IRouterProvider
-	validator      openapi.IValidator
-	sampleProvider samples.ISampleProvider
-	log            *logrus.Logger
+	cfg Config
+	log *logrus.Logger
+
+	// spec holds everything derived from the spec and the samples dir; a reload
+	// swaps it as a whole, so a request sees either the old or the new version.
+	spec     atomic.Pointer[specState]
+	reloadMu sync.Mutex
+	// providerCfg is the sample provider setup shared by every spec version.
+	providerCfg samples.ProviderConfig
 
 	scenario samples.IScenarioResolver
-}
+	sessions samples.ISessionStore
+	stubs    samples.IStubStore
 
-func New(cfg Config) (*Server, error) {
-	log := logger.GetLogger()
+	resources resources.IStore
 
-	specProvider, err := openapi.NewSpecProvider(cfg.SpecPath, log)
-	if err != nil {
-		return nil, err
-	}
+	upstream proxy.IProxy
+	recorder recorder.IRecorder
 
-	sp, ok := specProvider.(*openapi.SpecProvider)
-	if !ok {
-		return nil, fmt.Errorf("unexpected spec provider type: %T", specProvider)
-	}
+	journal journal.IJournal
+}
 
-	routeProvider := openapi.NewRouterProvider(sp.GetSpec())
-	validator := openapi.NewValidator(specProvider)
+func New(cfg Config) (*Server, error) {
+	log := logger.GetLogger()
 
 	if strings.TrimSpace(string(cfg.Layout)) == "" {
 		cfg.Layout = config.LayoutAuto
 	}
 
 	s := &Server{
-		cfg:            cfg,
-		specProvider:   specProvider,
-		routerProvider: routeProvider,
-		validator:      validator,
-		log:            log,
+		cfg: cfg,
+		log: log,
 	}
 
-	providerCfg := samples.ProviderConfig{
+	s.providerCfg = samples.ProviderConfig{
 		BaseDir:          cfg.SamplesDir,
 		Layout:           cfg.Layout,
 		ScenarioEnabled:  config.Envs.Scenario.Enabled,
 		ScenarioFilename: config.Envs.Scenario.Filename,
+		Templates:        cfg.Templates,
+		Stubs:            samples.NewStubStore(),
 	}
+	s.stubs = s.providerCfg.Stubs
 
 	if config.Envs.Scenario.Enabled {
-		s.scenario = samples.NewScenarioResolver()
-		providerCfg.ScenarioResolver = s.scenario
+		var clock samples.IClock = samples.RealClock{}
+		if config.Envs.Scenario.Clock == config.ClockVirtual {
+			clock = samples.NewVirtualClock(nil, true)
+		}
+		s.scenario = samples.NewScenarioResolverWithClock(clock)
+		s.providerCfg.Clock = clock
+		s.providerCfg.ScenarioResolver = s.scenario
+		// sessions keep their own steps and clocks; a session clock follows the
+		// global one until the session changes it
+		s.sessions = samples.NewSessionStore(func() samples.IScenarioResolver {
+			if global, ok := clock.(samples.IVirtualClock); ok {
+				return samples.NewScenarioResolverWithClock(samples.NewVirtualClock(global, false))
+			}
+			return samples.NewScenarioResolverWithClock(clock)
+		})
+		s.providerCfg.Sessions = s.sessions
 	}
 
-	s.sampleProvider = samples.NewSampleProvider(providerCfg, log)
+	st, err := s.loadSpecState()
+	if err != nil {
+		return nil, err
+	}
+	s.spec.Store(st)
+
+	if cfg.Resources.Enabled {
+		s.resources = resources.NewStore(cfg.Resources.IDField)
+	}
+
+	if cfg.Journal.Size > 0 || strings.TrimSpace(cfg.Journal.File) != "" {
+		s.journal, err = journal.NewJournal(journal.Config{Size: cfg.Journal.Size, File: cfg.Journal.File}, log)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, step := range cfg.FallbackMode.Steps() {
+		if step != config.FallbackNone && step != config.FallbackOpenAPIExample && step != config.FallbackProxy {
+			log.Warnf("unknown FALLBACK_MODE step %q is ignored", step)
+		}
+	}
+
+	if m := cfg.BasePath.Mode; m != "" && m != config.BasePathStrip && m != config.BasePathRequire {
+		log.Warnf("unknown BASE_PATH_MODE %q, base paths are optional", m)
+	}
+	if m := cfg.Generator.Mode; m != "" && m != config.GeneratorStatic && m != config.GeneratorSeeded {
+		log.Warnf("unknown GENERATOR_MODE %q, generating static values", m)
+	}
+
+	if cfg.Record || cfg.FallbackMode.Has(config.FallbackProxy) {
+		if strings.TrimSpace(cfg.Upstream.URL) == "" {
+			return nil, fmt.Errorf("RECORD_MODE and FALLBACK_MODE=proxy require UPSTREAM_URL")
+		}
+		s.upstream, err = proxy.NewProxy(proxy.Config{
+			URL:     cfg.Upstream.URL,
+			Timeout: time.Duration(cfg.Upstream.TimeoutSec) * time.Second,
+			Headers: cfg.Upstream.Headers,
+		}, log)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Record {
+		s.recorder = recorder.NewRecorder(recorder.Config{
+			SamplesDir:       cfg.SamplesDir,
+			ScenarioFilename: config.Envs.Scenario.Filename,
+		}, log)
+		log.Warnf("record mode: forwarding all requests to %s and writing samples to %s", cfg.Upstream.URL, cfg.SamplesDir)
+	}
 
 	return s, nil
 }
 
+// ListenAndServe serves until the process gets SIGINT or SIGTERM, then drains
+// open requests and closes the server.
 func (s *Server) ListenAndServe() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.handle)
@@ -91,11 +187,18 @@ func (s *Server) ListenAndServe() error {
 
 	s.log.Printf("mock listening on %s", addr)
 	s.log.Printf(
-		"spec=%s samples=%s fallback=%s validation=%s layout=%s scenario_enabled=%v scenario_file=%q",
-		s.cfg.SpecPath, s.cfg.SamplesDir, s.cfg.FallbackMode, s.cfg.ValidationMode,
-		s.cfg.Layout, config.Envs.Scenario.Enabled, config.Envs.Scenario.Filename,
+		"spec=%s samples=%s fallback=%s validation=%s response_validation=%s layout=%s scenario_enabled=%v scenario_file=%q resources_enabled=%v templates=%v record=%v journal_size=%d",
+		s.cfg.SpecPath, s.cfg.SamplesDir, s.cfg.FallbackMode, s.cfg.ValidationMode, s.cfg.ResponseValidationMode,
+		s.cfg.Layout, config.Envs.Scenario.Enabled, config.Envs.Scenario.Filename, s.cfg.Resources.Enabled, s.cfg.Templates, s.cfg.Record, s.cfg.Journal.Size,
 	)
 
+	if s.cfg.Watch.Enabled {
+		stop := make(chan struct{})
+		defer close(stop)
+		go s.watch(stop)
+		s.log.Infof("watching %s and %s for changes every %dms", s.cfg.SpecPath, s.cfg.SamplesDir, s.cfg.Watch.IntervalMs)
+	}
+
 	server := &http.Server{
 		Addr:              addr,
 		Handler:           mux,
@@ -105,7 +208,30 @@ func (s *Server) ListenAndServe() error {
 		IdleTimeout:       60 * time.Second,
 	}
 
-	return server.ListenAndServe()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return errors.Join(err, s.Close())
+	case <-ctx.Done():
+	}
+
+	s.log.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return errors.Join(server.Shutdown(shutdownCtx), s.Close())
+}
+
+// Close releases what the server keeps open: the journal file.
+func (s *Server) Close() error {
+	if s.journal == nil {
+		return nil
+	}
+	return s.journal.Close()
 }
 
 func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
@@ -118,19 +244,91 @@ func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rt := s.routerProvider.FindRoute(method, path)
+	if isAdminPath(path) {
+		s.handleAdmin(w, r)
+		return
+	}
+
+	if s.journal != nil {
+		s.serveJournaled(w, r)
+		return
+	}
+	s.serve(w, r, &resolution{})
+}
+
+// serve answers an API request and notes in res how it was resolved.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, res *resolution) {
+	method := r.Method
+	path := r.URL.Path
+
+	if s.recorder != nil {
+		res.source = journal.SourceUpstream
+		s.serveRecording(w, r, res)
+		return
+	}
+
+	st := s.current()
+	var rt *openapi.Route
+	var allowed []string
+	rel, ok := st.routerProvider.StripBasePath(path)
+	if ok {
+		rt = s.findRoute(st.routerProvider, method, rel)
+		if rt == nil {
+			allowed = st.routerProvider.AllowedMethods(rel)
+		}
+	}
+
+	// HEAD without its own operation is answered like GET, without the body
+	var head bool
+	if rt == nil && method == http.MethodHead && slices.Contains(allowed, http.MethodGet) {
+		rt = s.findRoute(st.routerProvider, http.MethodGet, rel)
+		head = true
+	}
+
 	if rt == nil {
-		utils.WriteJSON(w, 404, map[string]any{
-			"error":  "No route",
-			"method": method,
-			"path":   path,
-		})
+		switch {
+		case s.cfg.FallbackMode.Has(config.FallbackProxy):
+			res.source = journal.SourceUpstream
+			s.serveProxied(w, r)
+		case len(allowed) > 0 && method == http.MethodOptions:
+			w.Header().Set("Allow", allowHeader(allowed))
+			w.WriteHeader(http.StatusNoContent)
+		case len(allowed) > 0:
+			w.Header().Set("Allow", allowHeader(allowed))
+			utils.WriteJSON(w, 405, map[string]any{
+				"error":   "Method Not Allowed",
+				"method":  method,
+				"path":    path,
+				"allowed": allowed,
+			})
+		default:
+			utils.WriteJSON(w, 404, map[string]any{
+				"error":     "No route",
+				"method":    method,
+				"path":      path,
+				"basePaths": st.routerProvider.BasePaths(),
+			})
+		}
 		return
 	}
+	res.swagger = rt.Swagger
+	res.route = rel
+
+	// from here on the path is relative to the spec's path keys; the upstream gets the original
+	proxied := r
+	base := strings.TrimSuffix(path, strings.TrimSuffix(rel, "/"))
+	r = withPath(r, rel)
+	path = rel
+	if head {
+		w = headWriter{w}
+		r = withMethod(r, http.MethodGet)
+		method = http.MethodGet
+	}
 
-	if s.cfg.ValidationMode == config.ValidationRequired {
-		if s.validator.HasRequiredBodyParam(rt.Swagger, rt.Method) {
-			empty, err := s.validator.IsEmptyBody(r)
+	switch s.cfg.ValidationMode {
+	case config.ValidationRequired:
+		if st.validator.HasRequiredBodyParam(rt.Swagger, rt.Method) {
+			empty, err := st.validator.IsEmptyBody(r)
 			if err != nil {
 				utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
 				return
@@ -143,20 +341,100 @@ func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
+	case config.ValidationStrict:
+		if issues := st.validator.ValidateRequest(r, rt); len(issues) > 0 {
+			utils.WriteJSON(w, 400, map[string]any{
+				"error":       "Bad Request",
+				"details":     "Request does not conform to the API spec",
+				"method":      method,
+				"path":        path,
+				"swaggerPath": rt.Swagger,
+				"violations":  issues,
+			})
+			return
+		}
+	}
+
+	pref, err := preference(r)
+	if err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
 	}
 
-	resp, err := s.sampleProvider.ResolveAndLoad(
+	rc, err := requestContext(r)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	rc.PathParams = rt.Params
+	rc.Status = pref.Code
+
+	// Runtime stubs answer first. Named examples and generated bodies then come from
+	// the spec, and requests without a preference from the resource store.
+	useResources := s.resources != nil && pref.IsZero()
+	rc.StubsOnly = useResources || (pref.Example != "" || pref.Dynamic) && s.cfg.FallbackMode.Has(config.FallbackOpenAPIExample)
+
+	resp, err := st.sampleProvider.ResolveAndLoad(
 		method,
 		rt.Swagger,
 		path,
 		rt.SampleFile,
+		rc,
 	)
+	if useResources && errors.Is(err, samples.ErrNoStub) {
+		if s.serveResource(w, r, st, rt, base) {
+			res.source = journal.SourceResources
+			// sample resolution is skipped, but the request may still reset a scenario
+			if s.scenario != nil {
+				s.resolverFor(r).TryResetByRequest(method, path)
+			}
+			return
+		}
+		rc.StubsOnly = false
+		resp, err = st.sampleProvider.ResolveAndLoad(method, rt.Swagger, path, rt.SampleFile, rc)
+	}
+	if errors.Is(err, samples.ErrTemplate) || errors.Is(err, samples.ErrBodyFile) {
+		msg := "Sample template failed"
+		if errors.Is(err, samples.ErrBodyFile) {
+			msg = "Sample body file failed"
+		}
+		utils.WriteJSON(w, 500, map[string]any{
+			"error":       msg,
+			"method":      method,
+			"path":        path,
+			"swaggerPath": rt.Swagger,
+			"details":     err.Error(),
+		})
+		return
+	}
 	if err != nil {
-		if s.cfg.FallbackMode == config.FallbackOpenAPIExample {
-			if body, ok := s.specProvider.TryGetExampleBody(rt.Swagger, rt.Method); ok {
-				w.Header().Set("content-type", "application/json")
-				w.WriteHeader(200)
-				_, _ = w.Write(body) // #nosec G705: XSS via taint analysis
+		for _, step := range s.cfg.FallbackMode.Steps() {
+			switch step {
+			case config.FallbackOpenAPIExample:
+				if ex, ok := st.specProvider.TryGetExampleResponse(rt.Swagger, rt.Method, rt.Params, pref); ok {
+					res.source = journal.SourceSpecExample
+					for k, v := range ex.Headers {
+						w.Header().Set(k, v)
+					}
+					if ex.ContentType != "" {
+						w.Header().Set("content-type", ex.ContentType)
+					}
+					w.WriteHeader(ex.Status)
+					if len(ex.Body) > 0 {
+						_, _ = w.Write(ex.Body) // #nosec G705: XSS via taint analysis
+					}
+					return
+				}
+				if !pref.IsZero() {
+					writePreferenceNotDeclared(w, st, rt, pref)
+					return
+				}
+			case config.FallbackProxy:
+				res.source = journal.SourceUpstream
+				// r.Body was replaced by a re-readable copy; the original is drained
+				proxied = withURL(proxied, proxied.URL)
+				proxied.Body = r.Body
+				s.serveProxied(w, proxied)
 				return
 			}
 		}
@@ -169,11 +447,18 @@ func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
 			"legacyFlatFilename": rt.SampleFile,
 			"layout":             s.cfg.Layout,
 			"details":            err.Error(),
-			"hint":               "Create the sample file under SAMPLES_DIR/<path>/<METHOD>[.<state>].json (or legacy flat), or set FALLBACK_MODE=openapi_examples and add examples to swagger.json",
+			"hint":               "Create the sample file under SAMPLES_DIR/<path>/<METHOD>[.<state>].json (or legacy flat), or set FALLBACK_MODE=openapi_examples and add examples to swagger.json, or FALLBACK_MODE=proxy with UPSTREAM_URL",
 		})
 		return
 	}
 
+	res.source = s.relativeSource(resp.Source)
+	res.state = resp.State
+
+	if !s.checkSampleResponse(w, st, rt, resp) {
+		return
+	}
+
 	for k, v := range resp.Headers {
 		w.Header().Set(k, v)
 	}
@@ -181,10 +466,124 @@ func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(resp.Body) // #nosec G705: XSS via taint analysis
 }
 
+// findRoute routes a request. A loose match, whose path parameters fail their
+// schemas, only counts in strict validation mode and when no route accepts the
+// path for another method, so the validator answers 400 instead of a 404.
+func (s *Server) findRoute(rp openapi.IRouterProvider, method, path string) *openapi.Route {
+	rt := rp.FindRoute(method, path)
+	if rt != nil && rt.Loose && (s.cfg.ValidationMode != config.ValidationStrict || len(rp.AllowedMethods(path)) > 0) {
+		return nil
+	}
+	return rt
+}
+
+// requestContext captures query, headers and body of a request for sample resolution.
+// The body stays readable.
+// maxRequestBody is the largest request body the emulator reads.
+const maxRequestBody = 10 << 20
+
+var errBodyTooLarge = fmt.Errorf("request body exceeds %d bytes", maxRequestBody)
+
+func requestContext(r *http.Request) (*samples.RequestContext, error) {
+	rc := &samples.RequestContext{Query: r.URL.Query(), Headers: r.Header, Session: sessionID(r)}
+	if r.Body == nil || r.Body == http.NoBody {
+		return rc, nil
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxRequestBody {
+		return nil, errBodyTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	rc.Body = b
+	return rc, nil
+}
+
+// writeRequestError answers a request whose body could not be read.
+func writeRequestError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errBodyTooLarge) {
+		utils.WriteJSON(w, http.StatusRequestEntityTooLarge, map[string]any{"error": "Request Entity Too Large", "details": err.Error()})
+		return
+	}
+	utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+}
+
+// routeOperations maps every swagger path template to its HTTP methods.
+func routeOperations(rp openapi.IRouterProvider) map[string][]string {
+	out := map[string][]string{}
+	if rp == nil {
+		return out
+	}
+	for _, r := range rp.GetRoutes() {
+		out[r.Swagger] = append(out[r.Swagger], r.Method)
+	}
+	return out
+}
+
+// withPath returns a shallow copy of r with another URL path; the body is shared.
+func withPath(r *http.Request, path string) *http.Request {
+	if path == r.URL.Path {
+		return r
+	}
+	u := *r.URL
+	u.Path, u.RawPath = path, ""
+	return withURL(r, &u)
+}
+
+func withMethod(r *http.Request, method string) *http.Request {
+	r2 := r.WithContext(r.Context())
+	r2.Method = method
+	return r2
+}
+
+func withURL(r *http.Request, u *url.URL) *http.Request {
+	r2 := r.WithContext(r.Context())
+	r2.URL = u
+	return r2
+}
+
 func (s *Server) DebugRoutes() string {
-	out := ""
-	for _, r := range s.routerProvider.GetRoutes() {
+	rp := s.current().routerProvider
+	base := "/"
+	if paths := rp.BasePaths(); len(paths) > 0 {
+		base = strings.Join(paths, ", ")
+		if s.cfg.BasePath.Mode == config.BasePathRequire {
+			base += " (required)"
+		}
+	}
+	out := "base path: " + base + "\n"
+	for _, r := range rp.GetRoutes() {
 		out += fmt.Sprintf("%s %s -> %s\n", r.Method, r.Swagger, r.SampleFile)
 	}
 	return out
 }
+
+// allowHeader lists the declared methods plus the ones the emulator adds:
+// HEAD for GET routes, and OPTIONS.
+func allowHeader(methods []string) string {
+	set := map[string]bool{http.MethodOptions: true}
+	for _, m := range methods {
+		set[m] = true
+		if m == http.MethodGet {
+			set[http.MethodHead] = true
+		}
+	}
+	out := make([]string, 0, len(set))
+	for m := range set {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
+}
+
+// headWriter answers HEAD with the status and headers of a GET response, without the body.
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (hw headWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
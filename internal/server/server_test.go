@@ -36,20 +36,21 @@ func TestNew_LoadsSpecAndBuildsRoutes(t *testing.T) {
 	if s == nil {
 		t.Fatalf("expected server, got nil")
 	}
-	if s.specProvider == nil {
+	st := s.current()
+	if st.specProvider == nil {
 		t.Fatalf("expected specProvider")
 	}
-	if s.routerProvider == nil {
+	if st.routerProvider == nil {
 		t.Fatalf("expected routerProvider")
 	}
-	if s.validator == nil {
+	if st.validator == nil {
 		t.Fatalf("expected validator")
 	}
-	if s.sampleProvider == nil {
+	if st.sampleProvider == nil {
 		t.Fatalf("expected sampleProvider")
 	}
 
-	routes := s.routerProvider.GetRoutes()
+	routes := st.routerProvider.GetRoutes()
 	if len(routes) == 0 {
 		t.Fatalf("expected routes, got none")
 	}
@@ -130,6 +131,96 @@ func TestHandle_ValidationRequired_NonEmptyBody_AllowsSample(t *testing.T) {
 	}
 }
 
+func TestHandle_ValidationStrict_InvalidBody_400WithViolations(t *testing.T) {
+	s := newTestServer(t, config.ValidationStrict, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":1}`))
+	req.Header.Set("content-type", "application/json")
+
+	s.handle(rr, req)
+
+	if rr.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var m struct {
+		Error       string `json:"error"`
+		SwaggerPath string `json:"swaggerPath"`
+		Violations  []struct {
+			In      string `json:"in"`
+			Pointer string `json:"pointer"`
+			Message string `json:"message"`
+		} `json:"violations"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if m.Error != "Bad Request" || m.SwaggerPath != "/items" {
+		t.Fatalf("unexpected: %+v", m)
+	}
+	if len(m.Violations) != 1 || m.Violations[0].Pointer != "/body/name" || m.Violations[0].In != "body" {
+		t.Fatalf("unexpected violations: %+v", m.Violations)
+	}
+}
+
+func TestHandle_ValidationStrict_MalformedPathParam_400(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans/{id}":{
+		  "get":{
+			"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string","format":"uuid"}}],
+			"responses":{"200":{"description":"ok","content":{"application/json":{"example":{"id":"x"}}}}}
+		  }
+		}
+	  }
+	}`)
+
+	newServer := func(mode config.ValidationMode) *Server {
+		s, err := New(Config{
+			Port:           "0",
+			SpecPath:       specPath,
+			SamplesDir:     dir,
+			FallbackMode:   config.FallbackOpenAPIExample,
+			ValidationMode: mode,
+			Layout:         config.LayoutFolders,
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return s
+	}
+
+	rr, m := doRequest(newServer(config.ValidationStrict), http.MethodGet, "/scans/not-a-uuid", "")
+	violations, _ := m["violations"].([]any)
+	if rr.Code != 400 || len(violations) != 1 || violations[0].(map[string]any)["pointer"] != "/path/id" {
+		t.Fatalf("expected 400 with a /path/id violation, got %d %v", rr.Code, m)
+	}
+
+	if rr, _ := doRequest(newServer(config.ValidationNone), http.MethodGet, "/scans/not-a-uuid", ""); rr.Code != 404 {
+		t.Fatalf("expected 404 outside strict mode, got %d", rr.Code)
+	}
+}
+
+func TestHandle_ValidationStrict_ValidBody_AllowsSample(t *testing.T) {
+	s := newTestServer(t, config.ValidationStrict, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"x"}`))
+	req.Header.Set("content-type", "application/json")
+
+	s.handle(rr, req)
+
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
 func TestHandle_SampleFound_WritesHeadersStatusBody(t *testing.T) {
 	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
 
@@ -226,6 +317,47 @@ func TestHandle_SampleMissing_NoFallback_501(t *testing.T) {
 	}
 }
 
+func TestNew_BrokenScenario_FailsFastOrWarns(t *testing.T) {
+	prev := config.Envs.Scenario
+	t.Cleanup(func() { config.Envs.Scenario = prev })
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam":"itemId"},
+	  "sequence": [{"state":"a","file":"GET.a.json"}]
+	}`)
+
+	cfg := Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	}
+
+	config.Envs.Scenario = config.ScenarioConfig{Enabled: true, Filename: "scenario.json", Validation: config.ScenarioValidationFail}
+	_, err := New(cfg)
+	if err == nil {
+		t.Fatalf("expected startup error for broken scenario")
+	}
+	if !strings.Contains(err.Error(), `key.pathParam "itemId"`) || !strings.Contains(err.Error(), `"GET.a.json" does not exist`) {
+		t.Fatalf("expected aggregated report, got: %v", err)
+	}
+
+	config.Envs.Scenario.Validation = config.ScenarioValidationWarn
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("expected warn mode to start, got %v", err)
+	}
+	if s.scenario == nil {
+		t.Fatalf("expected scenario resolver")
+	}
+}
+
 func TestDebugRoutes_NotEmptyAndContainsMappings(t *testing.T) {
 	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
 
@@ -327,7 +459,10 @@ func minimalSpec() string {
 			  "required": true,
 			  "content":{
 				"application/json":{
-				  "schema":{"type":"object"}
+				  "schema":{
+					"type":"object",
+					"properties":{"name":{"type":"string"}}
+				  }
 				}
 			  }
 			},
@@ -346,3 +481,374 @@ func minimalSpec() string {
 	  }
 	}`
 }
+
+func TestHandle_Templates_EchoRequestAndFailLoudly(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"),
+		`{"id":{{ json .Path.id }},"trace":{{ json (index .Headers "x-trace") }},"q":{{ json .Query.q }}}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "POST.json"), `{"name":{{ .Body.name | json }`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+		Templates:      true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/42?q=x", nil)
+	req.Header.Set("X-Trace", "t-1")
+	s.handle(rr, req)
+
+	if rr.Code != 200 || strings.TrimSpace(rr.Body.String()) != `{"id":"42","trace":"t-1","q":"x"}` {
+		t.Fatalf("unexpected templated response: %d %s", rr.Code, rr.Body.String())
+	}
+
+	// a broken template must not silently fall back to the spec example
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"a"}`))
+	s.handle(rr, req)
+
+	if rr.Code != 500 {
+		t.Fatalf("expected 500 for a broken template, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_Variants_MatchOnRequestBody(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	writeFileWithDirs(t, dir, filepath.Join("items", "variants.json"), `{
+	  "version": 1,
+	  "variants": [
+		{"name":"conflict","method":"POST","match":{"body":{"$.name":"taken"}},"file":"POST.conflict.json"},
+		{"name":"debug","method":"POST","match":{"query":{"debug":{"present":true}}},"file":"POST.debug.json"}
+	  ]
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "POST.conflict.json"), `{"status":409,"body":{"error":"name taken"}}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "POST.debug.json"), `{"status":201,"body":{"debug":true}}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "POST.json"), `{"status":201,"body":{"created":true}}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationRequired,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	cases := []struct {
+		path, body string
+		status     int
+		want       string
+	}{
+		{"/items", `{"name":"taken"}`, 409, `{"error":"name taken"}`},
+		{"/items?debug", `{"name":"new"}`, 201, `{"debug":true}`},
+		{"/items", `{"name":"new"}`, 201, `{"created":true}`},
+	}
+	for _, tc := range cases {
+		rr, _ := doRequest(s, http.MethodPost, tc.path, tc.body)
+		if rr.Code != tc.status || strings.TrimSpace(rr.Body.String()) != tc.want {
+			t.Fatalf("%s %s: expected %d %s, got %d %s", tc.path, tc.body, tc.status, tc.want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestHandle_BasePath_FromServersAndRequired(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	spec := strings.Replace(minimalSpec(), `"paths":{`, `"servers":[{"url":"https://scanner.example.com/api/{version}","variables":{"version":{"default":"v1"}}}],
+	  "paths":{`, 1)
+	specPath := writeFile(t, dir, "spec.json", spec)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"), `{"id":"{{ .Path.id }}"}`)
+
+	newServer := func(bp config.BasePathConfig) *Server {
+		s, err := New(Config{
+			Port:           "0",
+			SpecPath:       specPath,
+			SamplesDir:     dir,
+			FallbackMode:   config.FallbackNone,
+			ValidationMode: config.ValidationNone,
+			Layout:         config.LayoutFolders,
+			Templates:      true,
+			BasePath:       bp,
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return s
+	}
+
+	s := newServer(config.BasePathConfig{})
+	for _, p := range []string{"/api/v1/items/7", "/items/7"} {
+		rr, m := doRequest(s, http.MethodGet, p, "")
+		if rr.Code != 200 || m["id"] != "7" {
+			t.Fatalf("%s: expected sample with path param, got %d %v", p, rr.Code, m)
+		}
+	}
+	if out := s.DebugRoutes(); !strings.Contains(out, "base path: /api/v1") {
+		t.Fatalf("expected base path in debug routes, got %q", out)
+	}
+
+	s = newServer(config.BasePathConfig{Mode: config.BasePathRequire})
+	if rr, _ := doRequest(s, http.MethodGet, "/items/7", ""); rr.Code != 404 {
+		t.Fatalf("expected 404 without the required base path, got %d", rr.Code)
+	}
+
+	s = newServer(config.BasePathConfig{Paths: []string{"/gmp"}, Mode: config.BasePathRequire})
+	if rr, _ := doRequest(s, http.MethodGet, "/gmp/items/7", ""); rr.Code != 200 {
+		t.Fatalf("expected override base path to route, got %d", rr.Code)
+	}
+	if rr, _ := doRequest(s, http.MethodGet, "/api/v1/items/7", ""); rr.Code != 404 {
+		t.Fatalf("expected spec base path to be replaced, got %d", rr.Code)
+	}
+}
+
+func TestHandle_BasePath_PathKeysWithBase(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	spec := `{
+	  "openapi":"3.0.0",
+	  "info":{"title":"x","version":"1"},
+	  "servers":[{"url":"/api/v1"}],
+	  "paths":{
+	    "/api/v1/items/{id}":{"get":{"responses":{"200":{"description":"ok"}}}}
+	  }
+	}`
+	specPath := writeFile(t, dir, "spec.json", spec)
+	writeFileWithDirs(t, dir, filepath.Join("api", "v1", "items", "{id}", "GET.json"), `{"id":"{{ .Path.id }}"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+		Templates:      true,
+		BasePath:       config.BasePathConfig{Mode: config.BasePathRequire},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr, m := doRequest(s, http.MethodGet, "/api/v1/items/7", "")
+	if rr.Code != 200 || m["id"] != "7" {
+		t.Fatalf("expected the unstripped path to route, got %d %v", rr.Code, m)
+	}
+}
+
+func TestHandle_MethodNotAllowed_405WithAllow(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	rr, m := doRequest(s, http.MethodDelete, "/items/1", "")
+	if rr.Code != 405 || m["error"] != "Method Not Allowed" {
+		t.Fatalf("expected 405, got %d %v", rr.Code, m)
+	}
+	if allow := rr.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+		t.Fatalf("unexpected Allow header %q", allow)
+	}
+
+	rr, _ = doRequest(s, http.MethodGet, "/items", "")
+	if rr.Code != 405 || rr.Header().Get("Allow") != "OPTIONS, POST" {
+		t.Fatalf("expected 405 with POST allowed, got %d %q", rr.Code, rr.Header().Get("Allow"))
+	}
+}
+
+func TestHandle_HeadAndOptions_Synthesized(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	rr, _ := doRequest(s, http.MethodHead, "/items/1", "")
+	if rr.Code != 200 || rr.Body.Len() != 0 {
+		t.Fatalf("expected bodiless 200, got %d %q", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("x-sample") != "1" {
+		t.Fatalf("expected headers of the GET sample, got %v", rr.Header())
+	}
+
+	rr, _ = doRequest(s, http.MethodHead, "/items", "")
+	if rr.Code != 405 {
+		t.Fatalf("expected 405 for HEAD without GET, got %d", rr.Code)
+	}
+
+	rr, _ = doRequest(s, http.MethodOptions, "/items/1", "")
+	if rr.Code != 204 || rr.Header().Get("Allow") != "GET, HEAD, OPTIONS" {
+		t.Fatalf("expected 204 with Allow, got %d %q", rr.Code, rr.Header().Get("Allow"))
+	}
+
+	rr, _ = doRequest(s, http.MethodOptions, "/nope", "")
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 for OPTIONS on an unknown path, got %d", rr.Code)
+	}
+}
+
+func TestHandle_ExplicitHeadOperationWins(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/health":{
+		  "get":{"responses":{"200":{"description":"ok"}}},
+		  "head":{"responses":{"204":{"description":"alive"}}}
+		}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("health", "GET.json"), `{"status":200,"body":{"ok":true}}`)
+	writeFileWithDirs(t, dir, filepath.Join("health", "HEAD.json"), `{"status":204,"headers":{"x-head":"own"}}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr, _ := doRequest(s, http.MethodHead, "/health", "")
+	if rr.Code != 204 || rr.Header().Get("x-head") != "own" {
+		t.Fatalf("expected the HEAD sample, got %d %v", rr.Code, rr.Header())
+	}
+}
+
+func TestHandle_SeededGeneration_VariesByPathParam(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans/{id}":{
+		  "get":{
+			"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+			"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{
+			  "type":"object",
+			  "properties":{
+				"id":{"type":"string","format":"uuid"},
+				"results":{"type":"array","items":{"type":"integer"}}
+			  }
+			}}}}}
+		  }
+		}
+	  }
+	}`)
+
+	newServer := func(gen config.GeneratorConfig) *Server {
+		s, err := New(Config{
+			Port:           "0",
+			SpecPath:       specPath,
+			SamplesDir:     dir,
+			FallbackMode:   config.FallbackOpenAPIExample,
+			ValidationMode: config.ValidationNone,
+			Layout:         config.LayoutFolders,
+			Generator:      gen,
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return s
+	}
+
+	get := func(s *Server, path string) string {
+		rr, _ := doRequest(s, http.MethodGet, path, "")
+		if rr.Code != 200 {
+			t.Fatalf("%s: expected 200, got %d", path, rr.Code)
+		}
+		return rr.Body.String()
+	}
+
+	seeded := config.GeneratorConfig{Mode: config.GeneratorSeeded, Seed: 7, ArrayMin: 5, ArrayMax: 5}
+	s := newServer(seeded)
+	a, b := get(s, "/scans/a"), get(s, "/scans/b")
+	if a == b {
+		t.Fatalf("expected different documents per id, got %s twice", a)
+	}
+	if again := get(newServer(seeded), "/scans/a"); again != a {
+		t.Fatalf("expected a stable document across servers, got %s and %s", a, again)
+	}
+
+	var doc struct {
+		Results []int `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(a), &doc); err != nil || len(doc.Results) != 5 {
+		t.Fatalf("expected 5 generated results, got %s", a)
+	}
+
+	static := newServer(config.GeneratorConfig{})
+	if get(static, "/scans/a") != get(static, "/scans/b") {
+		t.Fatal("expected the same static document for every id")
+	}
+}
+
+func TestHandle_SpecFallback_DeclaredStatusHeadersAndMediaType(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans":{
+		  "post":{"responses":{"201":{
+			"description":"created",
+			"headers":{"Location":{"schema":{"type":"string"},"example":"/scans/1"}},
+			"content":{"application/json":{"example":{"id":"1"}}}
+		  }}}
+		},
+		"/scans/{id}":{
+		  "delete":{"responses":{"204":{"description":"gone"}}},
+		  "patch":{"responses":{"202":{"description":"accepted","content":{"application/problem+json":{"example":{"title":"queued"}}}}}}
+		}
+	  }
+	}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr, m := doRequest(s, http.MethodPost, "/scans", `{}`)
+	if rr.Code != 201 || m["id"] != "1" || rr.Header().Get("Location") != "/scans/1" {
+		t.Fatalf("expected declared 201 with Location, got %d %v %v", rr.Code, rr.Header(), m)
+	}
+
+	rr, _ = doRequest(s, http.MethodDelete, "/scans/1", "")
+	if rr.Code != 204 || rr.Body.Len() != 0 || rr.Header().Get("content-type") != "" {
+		t.Fatalf("expected empty 204, got %d %q %v", rr.Code, rr.Body.String(), rr.Header())
+	}
+
+	rr, _ = doRequest(s, http.MethodPatch, "/scans/1", `{}`)
+	if rr.Code != 202 || rr.Header().Get("content-type") != "application/problem+json" {
+		t.Fatalf("expected 202 problem+json, got %d %v", rr.Code, rr.Header())
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// SessionHeader selects isolated scenario state for a request, so parallel
+// tests against one emulator do not advance each other's scenarios.
+const SessionHeader = "X-Emulator-Session"
+
+func sessionID(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(SessionHeader))
+}
+
+// resolverFor returns the scenario state an admin request acts on:
+// the session named by its header, or the shared default.
+func (s *Server) resolverFor(r *http.Request) samples.IScenarioResolver {
+	if id := sessionID(r); id != "" {
+		return s.sessions.Resolver(id)
+	}
+	return s.scenario
+}
+
+type sessionRequest struct {
+	ID string `json:"id"`
+}
+
+func (s *Server) adminListSessions(w http.ResponseWriter) {
+	if !s.requireScenarios(w) {
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"sessions": s.sessions.List()})
+}
+
+func (s *Server) adminCreateSession(w http.ResponseWriter, r *http.Request) {
+	if !s.requireScenarios(w) {
+		return
+	}
+
+	var req sessionRequest
+	if err := decodeAdminBody(r, &req); err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+
+	id, created := s.sessions.Create(strings.TrimSpace(req.ID))
+	status := 200
+	if created {
+		status = 201
+		s.log.WithFields(logrus.Fields{"session": id}).Info("session created via admin API")
+	}
+	utils.WriteJSON(w, status, map[string]any{"id": id, "header": SessionHeader})
+}
+
+func (s *Server) adminDeleteSession(w http.ResponseWriter, id string) {
+	if !s.requireScenarios(w) {
+		return
+	}
+	if !s.sessions.Delete(id) {
+		utils.WriteJSON(w, 404, map[string]any{"error": "Session not found", "id": id})
+		return
+	}
+	s.stubs.Reset(id)
+	s.log.WithFields(logrus.Fields{"session": id}).Info("session deleted via admin API")
+	utils.WriteJSON(w, 200, map[string]any{"deleted": id})
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+	"github.com/greenbone/gvm-openapi-emulator/internal/journal"
+)
+
+func doSessionRequest(s *Server, session, method, path, body string) (*httptest.ResponseRecorder, map[string]any) {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(method, "http://example.com"+path, strings.NewReader(body))
+	if session != "" {
+		req.Header.Set(SessionHeader, session)
+	}
+	s.handle(rr, req)
+
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	return rr, m
+}
+
+func TestSessions_IsolateScenarioState(t *testing.T) {
+	s := newScenarioServer(t)
+
+	state := func(session string) any {
+		_, m := doSessionRequest(s, session, http.MethodGet, "/items/abc", "")
+		return m["state"]
+	}
+
+	if got := state("a"); got != "queued" {
+		t.Fatalf("expected queued, got %v", got)
+	}
+	if got := state("a"); got != "running" {
+		t.Fatalf("expected running, got %v", got)
+	}
+	// another session and the shared default start from the beginning
+	if got := state("b"); got != "queued" {
+		t.Fatalf("expected session b to start at queued, got %v", got)
+	}
+	if got := state(""); got != "queued" {
+		t.Fatalf("expected default state to start at queued, got %v", got)
+	}
+
+	// admin calls act on the session named by the header
+	rr, _ := doSessionRequest(s, "b", http.MethodPost, "/__emulator/scenarios/state", `{"path":"/items/abc","state":"done"}`)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := state("b"); got != "done" {
+		t.Fatalf("expected session b to be done, got %v", got)
+	}
+	if got := state("a"); got != "done" {
+		t.Fatalf("expected session a to advance on its own, got %v", got)
+	}
+	_, m := doSessionRequest(s, "", http.MethodGet, "/__emulator/scenarios", "")
+	if list, _ := m["scenarios"].([]any); len(list) != 1 {
+		t.Fatalf("expected only the default key in the default state, got %v", m)
+	}
+}
+
+func TestAdmin_Sessions_CreateListDelete(t *testing.T) {
+	s := newScenarioServer(t)
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/sessions", `{"id":"t1"}`)
+	if rr.Code != 201 || m["id"] != "t1" || m["header"] != SessionHeader {
+		t.Fatalf("expected created session, got %d %v", rr.Code, m)
+	}
+	rr, _ = doRequest(s, http.MethodPost, "/__emulator/sessions", `{"id":"t1"}`)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200 for an existing session, got %d", rr.Code)
+	}
+	rr, m = doRequest(s, http.MethodPost, "/__emulator/sessions", "")
+	if rr.Code != 201 || len(m["id"].(string)) != 36 {
+		t.Fatalf("expected generated session id, got %d %v", rr.Code, m)
+	}
+
+	doSessionRequest(s, "t1", http.MethodGet, "/items/abc", "")
+	doSessionRequest(s, "t1", http.MethodGet, "/items/abc", "")
+
+	_, m = doRequest(s, http.MethodGet, "/__emulator/sessions", "")
+	list, _ := m["sessions"].([]any)
+	if len(list) != 2 {
+		t.Fatalf("expected 2 sessions, got %v", m)
+	}
+
+	rr, _ = doRequest(s, http.MethodDelete, "/__emulator/sessions/t1", "")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	rr, _ = doRequest(s, http.MethodDelete, "/__emulator/sessions/t1", "")
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 for a deleted session, got %d", rr.Code)
+	}
+
+	// a torn down session starts over when used again
+	_, m = doSessionRequest(s, "t1", http.MethodGet, "/items/abc", "")
+	if m["state"] != "queued" {
+		t.Fatalf("expected fresh state after delete, got %v", m)
+	}
+
+	off := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	rr, _ = doRequest(off, http.MethodPost, "/__emulator/sessions", "")
+	if rr.Code != 409 {
+		t.Fatalf("expected 409 when scenarios are disabled, got %d", rr.Code)
+	}
+}
+
+func TestSessions_JournalAndVerifyAreScoped(t *testing.T) {
+	s := newScenarioServer(t)
+	j, err := journal.NewJournal(journal.Config{Size: 10}, s.log)
+	if err != nil {
+		t.Fatalf("NewJournal: %v", err)
+	}
+	s.journal = j
+
+	doSessionRequest(s, "a", http.MethodGet, "/items/abc", "")
+	doSessionRequest(s, "b", http.MethodGet, "/items/abc", "")
+	doSessionRequest(s, "b", http.MethodGet, "/items/abc", "")
+
+	_, m := doRequest(s, http.MethodGet, "/__emulator/requests?session=b", "")
+	if list, _ := m["requests"].([]any); len(list) != 2 {
+		t.Fatalf("expected 2 requests of session b, got %v", m)
+	}
+
+	_, m = doSessionRequest(s, "a", http.MethodPost, "/__emulator/requests/verify", `{"method":"GET","path":"/items/abc","count":1}`)
+	if m["pass"] != true {
+		t.Fatalf("expected verification scoped to session a, got %v", m)
+	}
+}
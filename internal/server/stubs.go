@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/samples"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func (s *Server) adminCreateStub(w http.ResponseWriter, r *http.Request) {
+	var st samples.Stub
+	if err := decodeAdminBody(r, &st); err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+
+	// a stub registered within a session only answers that session
+	if st.Session == "" {
+		st.Session = sessionID(r)
+	}
+	st, err := s.stubs.Add(st)
+	if err != nil {
+		utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+		return
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"stub":    st.ID,
+		"session": st.Session,
+		"method":  st.Method,
+		"path":    st.Path,
+		"times":   st.Times,
+	}).Info("stub registered via admin API")
+	utils.WriteJSON(w, 201, st)
+}
+
+func (s *Server) adminDeleteStub(w http.ResponseWriter, id string) {
+	if !s.stubs.Delete(id) {
+		utils.WriteJSON(w, 404, map[string]any{"error": "Stub not found", "id": id})
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"deleted": id})
+}
+
+// adminResetStubs drops the stubs of the session named in the header, or all stubs without one.
+func (s *Server) adminResetStubs(w http.ResponseWriter, r *http.Request) {
+	session := sessionID(r)
+	n := s.stubs.Reset(session)
+	s.log.WithFields(logrus.Fields{"reset": n, "session": session}).Info("stubs cleared via admin API")
+	utils.WriteJSON(w, 200, map[string]any{"reset": n})
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
+
+func TestAdmin_Stubs_CreateServeListDelete(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	rr, m := doRequest(s, http.MethodPost, "/__emulator/stubs", `{
+	  "method": "GET",
+	  "path": "/items/{id}",
+	  "match": {"path": {"id": "42"}},
+	  "times": 2,
+	  "response": {"status": 503, "body": {"error": "maintenance"}}
+	}`)
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	id, _ := m["id"].(string)
+	if id == "" {
+		t.Fatalf("expected a generated id, got %v", m)
+	}
+
+	for i := 0; i < 2; i++ {
+		rr, _ = doRequest(s, http.MethodGet, "/items/42", "")
+		if rr.Code != 503 || strings.TrimSpace(rr.Body.String()) != `{"error":"maintenance"}` {
+			t.Fatalf("call %d: expected stub, got %d %s", i, rr.Code, rr.Body.String())
+		}
+	}
+	rr, m = doRequest(s, http.MethodGet, "/items/42", "")
+	if rr.Code != 200 || m["id"] != "123" {
+		t.Fatalf("expected sample after the stub is used up, got %d %v", rr.Code, m)
+	}
+	rr, _ = doRequest(s, http.MethodGet, "/items/7", "")
+	if rr.Code != 200 {
+		t.Fatalf("expected sample for a non-matching path, got %d", rr.Code)
+	}
+
+	_, m = doRequest(s, http.MethodGet, "/__emulator/stubs", "")
+	list, _ := m["stubs"].([]any)
+	if len(list) != 1 || list[0].(map[string]any)["hits"] != float64(2) {
+		t.Fatalf("unexpected stub list %v", m)
+	}
+
+	rr, _ = doRequest(s, http.MethodDelete, "/__emulator/stubs/"+id, "")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200 on delete, got %d", rr.Code)
+	}
+	rr, _ = doRequest(s, http.MethodDelete, "/__emulator/stubs/"+id, "")
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 for a deleted stub, got %d", rr.Code)
+	}
+}
+
+func TestAdmin_Stubs_BadInputAndReset(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	for _, body := range []string{
+		`{"times":-1}`,
+		`{"response":{"status":1000}}`,
+		`{"match":{"query":{"q":{"regex":"("}}}}`,
+		`not json`,
+	} {
+		rr, _ := doRequest(s, http.MethodPost, "/__emulator/stubs", body)
+		if rr.Code != 400 {
+			t.Fatalf("%s: expected 400, got %d", body, rr.Code)
+		}
+	}
+
+	doRequest(s, http.MethodPost, "/__emulator/stubs", `{"path":"/items"}`)
+	doRequest(s, http.MethodPost, "/__emulator/stubs", `{"path":"/items/1"}`)
+	_, m := doRequest(s, http.MethodPost, "/__emulator/stubs/reset", "")
+	if m["reset"] != float64(2) {
+		t.Fatalf("expected 2 stubs reset, got %v", m)
+	}
+}
+
+func TestAdmin_Stubs_SessionScoped(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	rr, m := doSessionRequest(s, "x", http.MethodPost, "/__emulator/stubs",
+		`{"method":"GET","path":"/items/{id}","response":{"status":503}}`)
+	if rr.Code != 201 || m["session"] != "x" {
+		t.Fatalf("expected a stub of session x, got %d %v", rr.Code, m)
+	}
+
+	if rr, _ = doSessionRequest(s, "x", http.MethodGet, "/items/42", ""); rr.Code != 503 {
+		t.Fatalf("expected the stub in session x, got %d", rr.Code)
+	}
+	for _, session := range []string{"", "y"} {
+		if rr, _ = doSessionRequest(s, session, http.MethodGet, "/items/42", ""); rr.Code != 200 {
+			t.Fatalf("session %q: expected the sample, got %d", session, rr.Code)
+		}
+	}
+
+	doRequest(s, http.MethodPost, "/__emulator/stubs", `{"method":"GET","path":"/items/{id}","response":{"status":502}}`)
+	_, m = doSessionRequest(s, "y", http.MethodPost, "/__emulator/stubs/reset", "")
+	if m["reset"] != float64(0) {
+		t.Fatalf("expected a reset in session y to keep other stubs, got %v", m)
+	}
+	_, m = doSessionRequest(s, "x", http.MethodPost, "/__emulator/stubs/reset", "")
+	if m["reset"] != float64(1) {
+		t.Fatalf("expected a reset in session x to drop its stub only, got %v", m)
+	}
+	if rr, _ = doSessionRequest(s, "x", http.MethodGet, "/items/42", ""); rr.Code != 502 {
+		t.Fatalf("expected the shared stub after the session reset, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/greenbone/gvm-openapi-emulator/internal/proxy"
+	"github.com/greenbone/gvm-openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// ProxiedHeader marks responses that came from the upstream instead of a sample.
+const ProxiedHeader = "X-Emulator-Proxied"
+
+// serveProxied answers a request the emulator cannot resolve with the upstream's response.
+func (s *Server) serveProxied(w http.ResponseWriter, r *http.Request) {
+	resp, ok := s.forward(w, r)
+	if !ok {
+		return
+	}
+	s.log.WithFields(logrus.Fields{
+		"method": r.Method,
+		"path":   r.URL.Path,
+		"status": resp.Status,
+	}).Debug("served from upstream")
+	writeUpstreamResponse(w, resp)
+}
+
+// serveRecording forwards a request to the upstream, answers with its response
+// and records that response as a sample of the matched route.
+func (s *Server) serveRecording(w http.ResponseWriter, r *http.Request, res *resolution) {
+	resp, ok := s.forward(w, r)
+	if !ok {
+		return
+	}
+
+	fields := logrus.Fields{"method": r.Method, "path": r.URL.Path, "status": resp.Status}
+	rp := s.current().routerProvider
+	rel, ok := rp.StripBasePath(r.URL.Path)
+	if rt := s.findRoute(rp, r.Method, rel); ok && rt != nil {
+		res.swagger, res.route = rt.Swagger, rel
+		if err := s.recorder.Record(r.Method, rt.Swagger, rel, resp); err != nil {
+			s.log.WithFields(fields).WithError(err).Warn("failed to record response")
+		} else {
+			s.log.WithFields(fields).WithField("swaggerPath", rt.Swagger).Info("recorded response")
+		}
+	} else {
+		s.log.WithFields(fields).Info("not recorded: no route in the spec")
+	}
+
+	writeUpstreamResponse(w, resp)
+}
+
+// forward sends r to the upstream. On failure it answers 502 and returns false.
+func (s *Server) forward(w http.ResponseWriter, r *http.Request) (*proxy.Response, bool) {
+	var body []byte
+	if r.Body != nil {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+			return nil, false
+		}
+		body = b
+	}
+
+	resp, err := s.upstream.Forward(r, body)
+	if err != nil {
+		utils.WriteJSON(w, 502, map[string]any{
+			"error":   "Upstream request failed",
+			"method":  r.Method,
+			"path":    r.URL.Path,
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+	return resp, true
+}
+
+func writeUpstreamResponse(w http.ResponseWriter, resp *proxy.Response) {
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(ProxiedHeader, "true")
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body) // #nosec G705: XSS via taint analysis
+}
@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenbone/gvm-openapi-emulator/config"
+)
+
+func TestRecord_ForwardsAndReplaysRecordedSamples(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("content-type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(201)
+			_, _ = w.Write([]byte(`{"created":true}`))
+		default:
+			_, _ = fmt.Fprintf(w, `{"id":%q,"call":%d}`, strings.TrimPrefix(r.URL.Path, "/items/"), calls)
+		}
+	}))
+	defer upstream.Close()
+
+	prev := config.Envs.Scenario
+	defer func() { config.Envs.Scenario = prev }()
+	config.Envs.Scenario.Enabled = true
+	config.Envs.Scenario.Filename = "scenario.json"
+	config.Envs.Scenario.Validation = config.ScenarioValidationFail
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	samplesDir := filepath.Join(dir, "samples")
+
+	rec, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     samplesDir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationRequired,
+		Layout:         config.LayoutFolders,
+		Upstream:       config.UpstreamConfig{URL: upstream.URL},
+		Record:         true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// validation does not apply while recording: the upstream decides
+	rr, m := doRequest(rec, http.MethodPost, "/items", "")
+	if rr.Code != 201 || m["created"] != true {
+		t.Fatalf("expected upstream response, got %d %s", rr.Code, rr.Body.String())
+	}
+	for i := 0; i < 2; i++ {
+		doRequest(rec, http.MethodGet, "/items/7", "")
+	}
+	rr, _ = doRequest(rec, http.MethodGet, "/unknown", "")
+	if rr.Code != 200 || calls != 4 {
+		t.Fatalf("expected unknown routes to be forwarded too, got %d after %d calls", rr.Code, calls)
+	}
+
+	replay, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     samplesDir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New (replay): %v", err)
+	}
+
+	rr, _ = doRequest(replay, http.MethodPost, "/items", "{}")
+	if rr.Code != 201 || strings.TrimSpace(rr.Body.String()) != `{"created":true}` {
+		t.Fatalf("unexpected replayed POST: %d %s", rr.Code, rr.Body.String())
+	}
+	for _, want := range []string{`{"call":2,"id":"7"}`, `{"call":3,"id":"7"}`, `{"call":3,"id":"7"}`} {
+		rr, _ = doRequest(replay, http.MethodGet, "/items/7", "")
+		if strings.TrimSpace(rr.Body.String()) != want {
+			t.Fatalf("expected recorded sequence %s, got %s", want, rr.Body.String())
+		}
+	}
+}
+
+func TestRecord_RequiresUpstreamAndReports502(t *testing.T) {
+	disableScenarioForTests()
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+
+	if _, err := New(Config{Port: "0", SpecPath: specPath, SamplesDir: dir, Record: true}); err == nil {
+		t.Fatalf("expected an error without UPSTREAM_URL")
+	}
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	s, err := New(Config{
+		Port:       "0",
+		SpecPath:   specPath,
+		SamplesDir: dir,
+		Upstream:   config.UpstreamConfig{URL: down.URL},
+		Record:     true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	rr, m := doRequest(s, http.MethodGet, "/items/1", "")
+	if rr.Code != 502 || m["error"] != "Upstream request failed" {
+		t.Fatalf("expected 502, got %d %v", rr.Code, m)
+	}
+}
+
+func newProxyFallbackServer(t *testing.T, fallback config.FallbackMode, upstreamURL string) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	writeFileWithDirs(t, dir, filepath.Join("items", "POST.json"), `{"status":201,"body":{"from":"sample"}}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   fallback,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+		Upstream: config.UpstreamConfig{
+			URL:     upstreamURL,
+			Headers: map[string]string{"Authorization": "Bearer upstream"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestProxyFallback_ForwardsUnresolvedRequests(t *testing.T) {
+	var auth string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("x-real", "1")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = fmt.Fprintf(w, `{"from":"upstream","path":%q}`, r.URL.Path)
+	}))
+	defer upstream.Close()
+
+	s := newProxyFallbackServer(t, config.FallbackProxy, upstream.URL)
+
+	// samples still win
+	rr, m := doRequest(s, http.MethodPost, "/items", "{}")
+	if rr.Code != 201 || m["from"] != "sample" || rr.Header().Get(ProxiedHeader) != "" {
+		t.Fatalf("expected sample, got %d %v", rr.Code, m)
+	}
+
+	// GET /items/{id} has no sample: the spec example is skipped because it is not in the chain
+	rr, m = doRequest(s, http.MethodGet, "/items/9", "")
+	if rr.Code != 202 || m["path"] != "/items/9" || rr.Header().Get("x-real") != "1" || rr.Header().Get(ProxiedHeader) != "true" {
+		t.Fatalf("expected proxied response, got %d %v %v", rr.Code, m, rr.Header())
+	}
+	if auth != "Bearer upstream" {
+		t.Fatalf("expected rewritten Authorization header, got %q", auth)
+	}
+
+	// routes outside the spec go upstream as well
+	rr, m = doRequest(s, http.MethodGet, "/not/in/spec", "")
+	if rr.Code != 202 || m["path"] != "/not/in/spec" {
+		t.Fatalf("expected proxied unknown route, got %d %v", rr.Code, m)
+	}
+}
+
+func TestProxyFallback_ChainedAfterSpecExamples(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"from":"upstream"}`))
+	}))
+	defer upstream.Close()
+
+	s := newProxyFallbackServer(t, "openapi_examples,proxy", upstream.URL)
+
+	rr, m := doRequest(s, http.MethodGet, "/items/9", "")
+	if rr.Code != 200 || m["id"] != "example" {
+		t.Fatalf("expected spec example first, got %d %v", rr.Code, m)
+	}
+
+	// DELETE /items/{id} is not in the spec, so it goes straight upstream
+	rr, m = doRequest(s, http.MethodDelete, "/items/9", "")
+	if rr.Code != 200 || m["from"] != "upstream" {
+		t.Fatalf("expected proxied response, got %d %v", rr.Code, m)
+	}
+}
+
+func TestProxyFallback_RequiresUpstream(t *testing.T) {
+	disableScenarioForTests()
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+
+	_, err := New(Config{Port: "0", SpecPath: specPath, SamplesDir: dir, FallbackMode: config.FallbackProxy})
+	if err == nil {
+		t.Fatalf("expected an error without UPSTREAM_URL")
+	}
+}
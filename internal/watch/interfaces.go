@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package watch
+
+type IWatcher interface {
+	// Run polls until stop is closed and calls onChange once the watched files
+	// changed and stayed unchanged for one more interval.
+	Run(stop <-chan struct{}, onChange func())
+}
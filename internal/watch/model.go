@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package watch
+
+import "time"
+
+type Config struct {
+	// Paths are files or directories; directories are watched recursively.
+	Paths    []string
+	Interval time.Duration
+}
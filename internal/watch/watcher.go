@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package watch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Watcher detects changes by polling file sizes and modification times.
+// Polling works on mounts without inotify support, e.g. read-only Docker volumes.
+type Watcher struct {
+	cfg Config
+	log *logrus.Logger
+}
+
+func NewWatcher(cfg Config, log *logrus.Logger) IWatcher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	return &Watcher{cfg: cfg, log: log}
+}
+
+func (w *Watcher) Run(stop <-chan struct{}, onChange func()) {
+	last := Fingerprint(w.cfg.Paths...)
+	pending := ""
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		fp := Fingerprint(w.cfg.Paths...)
+		switch {
+		case fp == last:
+			pending = ""
+		case fp != pending:
+			// wait one more interval, so a change that is still being written settles
+			pending = fp
+		default:
+			last, pending = fp, ""
+			w.log.WithFields(logrus.Fields{"paths": w.cfg.Paths}).Info("watched files changed")
+			onChange()
+		}
+	}
+}
+
+// Fingerprint hashes the name, size and modification time of every file below paths.
+// Missing or unreadable paths are part of the hash, so their appearance counts as a change.
+func Fingerprint(paths ...string) string {
+	h := sha256.New()
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				_, _ = fmt.Fprintf(h, "%s\x00error\n", p)
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(h, "%s\x00error\n", root)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/greenbone/gvm-openapi-emulator/logger"
+)
+
+func TestFingerprint_ChangesWithFiles(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.json")
+	require.NoError(t, os.WriteFile(spec, []byte(`{}`), 0o600))
+	samplesDir := filepath.Join(dir, "samples")
+
+	before := Fingerprint(spec, samplesDir)
+	require.Equal(t, before, Fingerprint(spec, samplesDir))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(samplesDir, "items"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(samplesDir, "items", "GET.json"), []byte(`{}`), 0o600))
+	added := Fingerprint(spec, samplesDir)
+	require.NotEqual(t, before, added, "new sample file")
+
+	require.NoError(t, os.WriteFile(spec, []byte(`{"x":1}`), 0o600))
+	require.NotEqual(t, added, Fingerprint(spec, samplesDir), "edited spec")
+}
+
+func TestWatcher_Run_CallsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "spec.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{}`), 0o600))
+
+	w := NewWatcher(Config{Paths: []string{dir}, Interval: 10 * time.Millisecond}, logger.GetLogger())
+	changed := make(chan struct{}, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go w.Run(stop, func() { changed <- struct{}{} })
+
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte(`{"changed":true}`), 0o600))
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected onChange after the file changed")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
 )
 
@@ -158,3 +159,11 @@ func TestFileExists(t *testing.T) {
 		}
 	})
 }
+
+func TestNewUUID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a, b := NewUUID(), NewUUID()
+	if !re.MatchString(a) || a == b {
+		t.Fatalf("expected two distinct version 4 UUIDs, got %q and %q", a, b)
+	}
+}